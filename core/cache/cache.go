@@ -0,0 +1,337 @@
+// Package cache provides an LRU cache for decoded PDF extraction results
+// (mupdf.StructuredBlock slices, Page batches, …) keyed on
+// (file hash, mode, options hash). Besides the usual count-based LRU
+// eviction, it watches process RSS via periodic sampling and, once RSS
+// crosses a configurable fraction of total system memory, evicts
+// least-recently-used entries down to a high-water mark, so a cache full
+// of multi-thousand-page PDFs can't push the host into swap.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+const (
+	defaultMaxEntries     = 256
+	defaultMemoryFraction = 0.25
+	defaultSampleInterval = 5 * time.Second
+	fallbackSystemMemory  = 4 << 30 // 4 GiB, used when /proc/meminfo is unavailable
+
+	// evictionTargetFraction is the high-water mark (as a fraction of
+	// maxBytes) that a memory-pressure eviction pass drains down to, so a
+	// single tick over the limit doesn't empty the cache in one shot.
+	evictionTargetFraction = 0.9
+)
+
+// Stats is a point-in-time snapshot of cache health, suitable for logging.
+type Stats struct {
+	Entries         int
+	Bytes           int64
+	Hits            int64
+	Misses          int64
+	Evictions       int64
+	MemoryEvictions int64
+}
+
+// Cache is a count- and memory-pressure-bounded LRU. Zero value is not
+// usable; construct with New.
+type Cache struct {
+	mu         sync.Mutex
+	ll         *list.List
+	items      map[string]*list.Element
+	maxEntries int
+	maxBytes   int64
+	curBytes   int64
+	logger     *slog.Logger
+
+	hits, misses, evictions, memEvictions int64
+
+	stopSampler chan struct{}
+}
+
+type entry struct {
+	key   string
+	value any
+	size  int64
+}
+
+// Option configures a Cache at construction time.
+type Option func(*Cache)
+
+// WithMaxEntries caps the number of cached entries regardless of memory
+// pressure. Default 256.
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) { c.maxEntries = n }
+}
+
+// WithMemoryFraction sets the fraction (0,1] of total system memory that,
+// once the process RSS crosses it, triggers LRU eviction. Default 0.25.
+// The GIZMO_MEMORY_LIMIT env var (absolute gigabytes), when set, replaces
+// that default; an explicit WithMemoryFraction still takes precedence over
+// the env var, since options are applied after it.
+func WithMemoryFraction(f float64) Option {
+	return func(c *Cache) { c.maxBytes = int64(f * float64(totalSystemMemory())) }
+}
+
+// WithLogger injects a slog.Logger for eviction/stat observability.
+// nil ⇒ slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Cache) { c.logger = l }
+}
+
+// New constructs a Cache and starts its background memory sampler. Call
+// Close to stop the sampler goroutine.
+func New(opts ...Option) *Cache {
+	c := &Cache{
+		ll:          list.New(),
+		items:       make(map[string]*list.Element),
+		maxEntries:  defaultMaxEntries,
+		maxBytes:    int64(defaultMemoryFraction * float64(totalSystemMemory())),
+		logger:      slog.Default(),
+		stopSampler: make(chan struct{}),
+	}
+	if gb := memoryLimitOverrideGB(); gb > 0 {
+		c.maxBytes = int64(gb * 1e9)
+	}
+	for _, o := range opts {
+		if o != nil {
+			o(c)
+		}
+	}
+	if c.logger == nil {
+		c.logger = slog.Default()
+	}
+	go c.sampleLoop()
+	return c
+}
+
+// Close stops the background memory sampler.
+func (c *Cache) Close() {
+	close(c.stopSampler)
+}
+
+// Get returns the cached value for key, promoting it to most-recently-used.
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	c.hits++
+	return el.Value.(*entry).value, true
+}
+
+// Put stores value under key with an approximate size in bytes (used only
+// for memory-pressure accounting), evicting LRU entries as needed.
+func (c *Cache) Put(key string, value any, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		old := el.Value.(*entry)
+		c.curBytes += size - old.size
+		old.value, old.size = value, size
+		c.ll.MoveToFront(el)
+	} else {
+		el := c.ll.PushFront(&entry{key: key, value: value, size: size})
+		c.items[key] = el
+		c.curBytes += size
+	}
+
+	for c.ll.Len() > c.maxEntries {
+		c.evictOldest(false)
+	}
+}
+
+// Stats returns a snapshot of cache counters.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Entries:         c.ll.Len(),
+		Bytes:           c.curBytes,
+		Hits:            c.hits,
+		Misses:          c.misses,
+		Evictions:       c.evictions,
+		MemoryEvictions: c.memEvictions,
+	}
+}
+
+// evictOldest drops the least-recently-used entry. Caller must hold c.mu.
+func (c *Cache) evictOldest(memoryPressure bool) {
+	el := c.ll.Back()
+	if el == nil {
+		return
+	}
+	e := el.Value.(*entry)
+	c.ll.Remove(el)
+	delete(c.items, e.key)
+	c.curBytes -= e.size
+	if memoryPressure {
+		c.memEvictions++
+	} else {
+		c.evictions++
+	}
+}
+
+// sampleLoop periodically samples process RSS and evicts LRU entries when
+// it exceeds maxBytes.
+func (c *Cache) sampleLoop() {
+	ticker := time.NewTicker(defaultSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopSampler:
+			return
+		case <-ticker.C:
+			c.evictUnderMemoryPressure()
+		}
+	}
+}
+
+// evictUnderMemoryPressure is triggered once process RSS crosses maxBytes,
+// but bounds the actual eviction loop against the cache's own curBytes (RSS
+// isn't released back to the OS as entries are dropped, so looping on RSS
+// would drain the cache to empty on a single tick). It evicts down to
+// evictionTargetFraction of maxBytes rather than to zero, so a brief spike
+// doesn't cold-start the whole cache.
+func (c *Cache) evictUnderMemoryPressure() {
+	rss := processRSS()
+	if rss <= c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := int64(float64(c.maxBytes) * evictionTargetFraction)
+	evicted := 0
+	for c.curBytes > target && c.ll.Len() > 0 {
+		c.evictOldest(true)
+		evicted++
+	}
+	if evicted > 0 {
+		c.logger.Info("core/cache: evicted entries under memory pressure",
+			"evicted", evicted, "rss_bytes", rss, "limit_bytes", c.maxBytes,
+			"cur_bytes", c.curBytes, "entries_left", c.ll.Len())
+	}
+}
+
+// processRSS returns the process's resident set size in bytes, read from
+// /proc/self/status on Linux. It falls back to runtime.MemStats.Sys (which
+// measures reserved address space, not RSS, but is the closest portable
+// proxy) when /proc is unavailable, for the same reason totalSystemMemory
+// falls back to a fixed estimate.
+func processRSS() int64 {
+	b, err := os.ReadFile("/proc/self/status")
+	if err == nil {
+		for _, line := range splitLines(b) {
+			var kb int64
+			if n, _ := fmt.Sscanf(line, "VmRSS: %d kB", &kb); n == 1 {
+				return kb * 1024
+			}
+		}
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return int64(ms.Sys)
+}
+
+// totalSystemMemory returns total physical memory in bytes, read from
+// /proc/meminfo on Linux. It falls back to a fixed 4 GiB estimate on other
+// platforms or if the read fails, since gizmo avoids OS-specific deps for
+// this.
+func totalSystemMemory() int64 {
+	b, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return fallbackSystemMemory
+	}
+	for _, line := range splitLines(b) {
+		var kb int64
+		if n, _ := fmt.Sscanf(line, "MemTotal: %d kB", &kb); n == 1 {
+			return kb * 1024
+		}
+	}
+	return fallbackSystemMemory
+}
+
+func splitLines(b []byte) []string {
+	var lines []string
+	start := 0
+	for i, c := range b {
+		if c == '\n' {
+			lines = append(lines, string(b[start:i]))
+			start = i + 1
+		}
+	}
+	return lines
+}
+
+// memoryLimitOverrideGB reads GIZMO_MEMORY_LIMIT (gigabytes) if set.
+func memoryLimitOverrideGB() float64 {
+	v := os.Getenv("GIZMO_MEMORY_LIMIT")
+	if v == "" {
+		return 0
+	}
+	gb, err := strconv.ParseFloat(v, 64)
+	if err != nil || gb <= 0 {
+		return 0
+	}
+	return gb
+}
+
+// ----------------------------------------------------------------------------
+// Key helpers
+// ----------------------------------------------------------------------------
+
+// Key builds a cache key from a file hash, adapter mode, and options hash.
+func Key(fileHash, mode, optsHash string) string {
+	return fileHash + "|" + mode + "|" + optsHash
+}
+
+// HashFile returns the hex-encoded sha256 of r's entire contents. Callers
+// open r through whatever core.FS the caller is configured with, so the
+// cache works the same whether the input is on local disk or not.
+func HashFile(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashOptions returns a stable hash of the parts of cfg that affect an
+// adapter's output (Pages, Format, Extra), so two calls with equivalent
+// options share a cache entry.
+func HashOptions(cfg *core.Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "pages=%v;format=%s;", cfg.Pages, cfg.Format)
+
+	keys := make([]string, 0, len(cfg.Extra))
+	for k := range cfg.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%v;", k, cfg.Extra[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
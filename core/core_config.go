@@ -13,11 +13,14 @@ import (
 // All fields are optional; zero values fall back to sensible defaults.
 
 type Config struct {
-	Pages   []int          // 1‑based page numbers; empty ⇒ all pages
-	Format  string         // output format hint: "text", "png", … – adapter decides validity
-	Extra   map[string]any // adapter‑specific key/value bag (string keys)
-	WorkDir string         // override for any temp files the adapter needs
-	Logger  *slog.Logger   // nil ⇒ slog.Default()
+	Pages            []int                 // 1‑based page numbers; empty ⇒ all pages
+	Format           string                // output format hint: "text", "png", … – adapter decides validity
+	Extra            map[string]any        // adapter‑specific key/value bag (string keys)
+	WorkDir          string                // override for any temp files the adapter needs
+	Logger           *slog.Logger          // nil ⇒ slog.Default()
+	Cache            bool                  // opt in to result caching (see core/cache)
+	ProgressCallback func(done, total int) // nil ⇒ no progress reporting
+	FS               FS                    // nil ⇒ OSFS{} (see core_fs.go)
 }
 
 // Option mutates a Config – classic functional‑options pattern.
@@ -56,24 +59,41 @@ func WithLogger(l *slog.Logger) Option {
 	return func(c *Config) { c.Logger = l }
 }
 
+// WithCache opts an adapter call into result caching, where supported
+// (e.g. mupdf.ExtractStructuredText). See core/cache for the cache itself.
+func WithCache(enabled bool) Option {
+	return func(c *Config) { c.Cache = enabled }
+}
+
+// WithProgressCallback registers a callback invoked as an adapter makes
+// incremental progress, e.g. per page or per batch. done and total are in
+// whatever unit the adapter reports (mupdf reports pages).
+func WithProgressCallback(cb func(done, total int)) Option {
+	return func(c *Config) { c.ProgressCallback = cb }
+}
+
 // BuildConfig applies Option setters over defaults and returns the result.
 // The returned Config is safe for concurrent read‑only access.
 func BuildConfig(opts ...Option) *Config {
 	cfg := &Config{
 		Extra:  make(map[string]any, 4),
 		Logger: slog.Default(),
+		FS:     OSFS{},
 	}
 	for _, o := range opts {
 		if o != nil {
 			o(cfg)
 		}
 	}
-	// Guarantee Extra is non‑nil for adapters; ensure Logger fallback
+	// Guarantee Extra is non‑nil for adapters; ensure Logger/FS fallback
 	if cfg.Extra == nil {
 		cfg.Extra = make(map[string]any)
 	}
 	if cfg.Logger == nil {
 		cfg.Logger = slog.Default()
 	}
+	if cfg.FS == nil {
+		cfg.FS = OSFS{}
+	}
 	return cfg
 }
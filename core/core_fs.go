@@ -0,0 +1,44 @@
+// core_fs.go
+// FS lets adapters read/write through a pluggable filesystem instead of
+// hard-coding the local disk, so inputs can come from S3, GCS, in-memory
+// buffers, or test fixtures (see an in-memory implementation for tests).
+
+package core
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// File is the handle FS.Open/FS.Create return. *os.File already satisfies
+// it, so OSFS needs no adapter type.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+	Name() string
+}
+
+// FS abstracts the handful of filesystem operations gizmo's adapters need.
+// Implementations should be safe for concurrent use.
+type FS interface {
+	Open(name string) (File, error)
+	Create(name string) (File, error)
+	Stat(name string) (fs.FileInfo, error)
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// OSFS is the default FS, backed by the local disk via the os package.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (File, error)               { return os.Open(name) }
+func (OSFS) Create(name string) (File, error)             { return os.Create(name) }
+func (OSFS) Stat(name string) (fs.FileInfo, error)        { return os.Stat(name) }
+func (OSFS) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+// WithFS overrides the filesystem an adapter reads/writes through. Nil or
+// unset ⇒ OSFS{}.
+func WithFS(fsys FS) Option {
+	return func(c *Config) { c.FS = fsys }
+}
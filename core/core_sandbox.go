@@ -0,0 +1,58 @@
+// core_sandbox.go
+// SandboxConfig is the adapter-agnostic description of how an adapter should
+// isolate any external subprocess it shells out to (e.g. mupdf's mutool).
+// The actual sandboxing backends live in core/sandbox to avoid pulling OCI
+// runtime dependencies into every consumer of core.
+
+package core
+
+// SandboxConfig requests that an adapter run its external subprocess inside
+// an isolated environment rather than invoking it directly. Pass it via
+// WithExtra("sandbox", core.SandboxConfig{...}); adapters that don't support
+// sandboxing simply ignore the key.
+//
+// Under the runc/crun backends the zero value still confines the
+// subprocess to a minimal rootfs (its shared libraries, the input file, and
+// a scratch dir – never the host root) under its own PID/mount/IPC/UTS
+// namespaces; bwrap gives the same minimal rootfs but does not unshare the
+// PID, IPC or UTS namespaces; the exec backend, which auto-detection ends
+// on when no runtime is installed, gives no isolation at all. Every
+// optional hardening knob below (network isolation, capability dropping,
+// seccomp, resource limits) defaults off. Callers that need those should
+// set them explicitly; don't rely on the zero value alone for untrusted
+// input.
+type SandboxConfig struct {
+	// Backend selects the isolation mechanism: "runc", "crun", "bwrap", or
+	// "exec" (no real isolation, just the subprocess – useful when no OCI
+	// runtime is installed). Empty ⇒ auto-detect in this preference order.
+	Backend string
+
+	// NoNetwork drops the network namespace so the subprocess cannot reach
+	// the network at all.
+	NoNetwork bool
+
+	// ReadOnlyRootfs also locks down the scratch work directory to
+	// read-only. The rest of the sandbox rootfs (shared libraries, the
+	// input file) is always read-only regardless of this flag; set this
+	// when the adapter's subprocess doesn't need to write anything at all.
+	ReadOnlyRootfs bool
+
+	// DropAllCapabilities strips every Linux capability from the subprocess.
+	DropAllCapabilities bool
+
+	// Seccomp applies a default deny-dangerous-syscalls seccomp profile.
+	// Only the runc/crun backends apply it; bwrap and exec run without a
+	// seccomp filter.
+	Seccomp bool
+
+	// MemoryLimitBytes caps the subprocess's memory usage. 0 ⇒ no limit.
+	MemoryLimitBytes int64
+
+	// CPUQuotaMicros caps CPU time to CPUQuotaMicros of every 100ms period
+	// (cgroup cpu.cfs_quota_us semantics). 0 ⇒ no limit.
+	CPUQuotaMicros int64
+
+	// OOMScoreAdj adjusts the subprocess's likelihood of being killed under
+	// memory pressure (-1000..1000, see proc(5) oom_score_adj).
+	OOMScoreAdj int
+}
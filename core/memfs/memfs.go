@@ -0,0 +1,126 @@
+// Package memfs provides an in-memory core.FS implementation for tests, so
+// adapter tests don't depend on fixture files existing on the local disk.
+package memfs
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+// FS is an in-memory filesystem: Open/Create/Stat/MkdirAll operate against a
+// map instead of the local disk. The zero value is empty and ready to use;
+// seed it with New or by calling Create.
+type FS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// New returns an FS pre-populated with seed's paths and contents.
+func New(seed map[string][]byte) *FS {
+	files := make(map[string][]byte, len(seed))
+	for name, content := range seed {
+		files[name] = append([]byte(nil), content...)
+	}
+	return &FS{files: files, dirs: make(map[string]bool)}
+}
+
+// Open returns a reader over name's current contents, or an fs.ErrNotExist
+// *fs.PathError if name hasn't been written.
+func (f *FS) Open(name string) (core.File, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	content, ok := f.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &file{fs: f, name: name, reader: bytes.NewReader(content)}, nil
+}
+
+// Create returns a writer that commits its contents to name on Close.
+func (f *FS) Create(name string) (core.File, error) {
+	return &file{fs: f, name: name, writer: &bytes.Buffer{}}, nil
+}
+
+// Stat returns a minimal fs.FileInfo for name.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dirs[name] {
+		return fileInfo{name: name, isDir: true}, nil
+	}
+	content, ok := f.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return fileInfo{name: name, size: int64(len(content))}, nil
+}
+
+// MkdirAll records path as an existing directory; memfs has no real
+// hierarchy, so this never fails.
+func (f *FS) MkdirAll(path string, perm fs.FileMode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dirs == nil {
+		f.dirs = make(map[string]bool)
+	}
+	f.dirs[path] = true
+	return nil
+}
+
+// file implements core.File over either a read buffer (Open) or a write
+// buffer (Create) – never both.
+type file struct {
+	fs     *FS
+	name   string
+	reader *bytes.Reader
+	writer *bytes.Buffer
+}
+
+func (fl *file) Read(p []byte) (int, error) {
+	if fl.reader == nil {
+		return 0, fmt.Errorf("memfs: %s not open for reading", fl.name)
+	}
+	return fl.reader.Read(p)
+}
+
+func (fl *file) Write(p []byte) (int, error) {
+	if fl.writer == nil {
+		return 0, fmt.Errorf("memfs: %s not open for writing", fl.name)
+	}
+	return fl.writer.Write(p)
+}
+
+func (fl *file) Close() error {
+	if fl.writer != nil {
+		fl.fs.mu.Lock()
+		if fl.fs.files == nil {
+			fl.fs.files = make(map[string][]byte)
+		}
+		fl.fs.files[fl.name] = append([]byte(nil), fl.writer.Bytes()...)
+		fl.fs.mu.Unlock()
+	}
+	return nil
+}
+
+func (fl *file) Name() string { return fl.name }
+
+type fileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi fileInfo) Name() string       { return fi.name }
+func (fi fileInfo) Size() int64        { return fi.size }
+func (fi fileInfo) Mode() fs.FileMode  { return 0o644 }
+func (fi fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi fileInfo) IsDir() bool        { return fi.isDir }
+func (fi fileInfo) Sys() any           { return nil }
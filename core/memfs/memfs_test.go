@@ -0,0 +1,34 @@
+package memfs
+
+import "testing"
+
+func TestZeroValue_CreateAndMkdirAll(t *testing.T) {
+	var fsys FS
+
+	f, err := fsys.Create("out.pdf")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := f.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if err := fsys.MkdirAll("scratch", 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+
+	rf, err := fsys.Open("out.pdf")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := rf.Read(buf); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("read contents = %q, want %q", buf, "hello")
+	}
+}
@@ -0,0 +1,56 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+// execBackend runs the subprocess directly via os/exec with no namespace or
+// filesystem isolation – the fallback when neither an OCI runtime nor
+// bubblewrap is installed. It still applies whatever limits the kernel
+// exposes without a container (currently OOMScoreAdj) and logs a warning
+// about the constraints it cannot honor.
+type execBackend struct{}
+
+func (execBackend) run(ctx context.Context, req Request, cfg core.SandboxConfig) error {
+	logger := req.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("sandbox: no OCI runtime or bwrap available, running without isolation",
+		"no_network", cfg.NoNetwork, "readonly_rootfs", cfg.ReadOnlyRootfs,
+		"drop_capabilities", cfg.DropAllCapabilities, "seccomp", cfg.Seccomp)
+
+	cmd := exec.CommandContext(ctx, req.Bin, req.Args...)
+	cmd.Stdout = req.Stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("sandbox: exec fallback start: %w", err)
+	}
+	if cfg.OOMScoreAdj != 0 {
+		if err := setOOMScoreAdj(cmd.Process.Pid, cfg.OOMScoreAdj); err != nil {
+			logger.Warn("sandbox: failed to set oom_score_adj", "error", err)
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("sandbox: exec fallback: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// setOOMScoreAdj writes /proc/<pid>/oom_score_adj, the same knob the runc
+// backend sets through the spec's process.oomScoreAdj (the bwrap backend
+// does not set it).
+func setOOMScoreAdj(pid, score int) error {
+	path := fmt.Sprintf("/proc/%d/oom_score_adj", pid)
+	return os.WriteFile(path, []byte(strconv.Itoa(score)), 0o644)
+}
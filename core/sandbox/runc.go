@@ -0,0 +1,200 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+// runcBackend drives an OCI-compliant runtime (runc or crun) against a
+// generated runtime-spec bundle: a purpose-built minimal rootfs containing
+// only the shared libraries and binary mutool needs, plus the input file and
+// a scratch work dir bound in individually. The host root is never exposed.
+type runcBackend struct {
+	bin string // resolved path to runc or crun
+}
+
+func newRuncBackend(exe string) (backend, string, error) {
+	path, err := lookPath(exe)
+	if err != nil {
+		return nil, "", err
+	}
+	return &runcBackend{bin: path}, exe, nil
+}
+
+func (b *runcBackend) run(ctx context.Context, req Request, cfg core.SandboxConfig) error {
+	// The bundle (config.json and the generated rootfs) is host-side
+	// bookkeeping for runc, not scratch space for the sandboxed process, so
+	// it must live outside req.WorkDir – otherwise WorkDir's own bind mount
+	// below would fold the bundle (and its rootfs) back into itself.
+	bundleDir, err := os.MkdirTemp("", "gizmo-sandbox-bundle-*")
+	if err != nil {
+		return fmt.Errorf("sandbox: creating bundle dir: %w", err)
+	}
+	defer os.RemoveAll(bundleDir)
+
+	rootfsDir := filepath.Join(bundleDir, "rootfs")
+	spec, err := buildSpec(rootfsDir, req, cfg)
+	if err != nil {
+		return fmt.Errorf("sandbox: building rootfs: %w", err)
+	}
+	specPath := filepath.Join(bundleDir, "config.json")
+	f, err := os.Create(specPath)
+	if err != nil {
+		return fmt.Errorf("sandbox: writing config.json: %w", err)
+	}
+	if err := json.NewEncoder(f).Encode(spec); err != nil {
+		f.Close()
+		return fmt.Errorf("sandbox: encoding config.json: %w", err)
+	}
+	f.Close()
+
+	id := filepath.Base(bundleDir)
+	args := []string{"run", "--bundle", bundleDir, id}
+	cmd := exec.CommandContext(ctx, b.bin, args...)
+	cmd.Stdout = req.Stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("sandbox: %s run: %w: %s", filepath.Base(b.bin), err, stderr.String())
+	}
+	return nil
+}
+
+// buildSpec translates a Request + SandboxConfig into a minimal OCI
+// runtime-spec. rootfsDir is populated with only what mutool needs to run:
+// its shared libraries and own directory (read-only), the input file
+// (read-only), and req.WorkDir as scratch space when set (writable unless
+// cfg.ReadOnlyRootfs) – never the host root. Root.Readonly is always true,
+// since rootfsDir itself is just bare mountpoint directories with nothing
+// worth writing to.
+func buildSpec(rootfsDir string, req Request, cfg core.SandboxConfig) (*specs.Spec, error) {
+	mounts := []specs.Mount{
+		{Destination: "/proc", Type: "proc", Source: "proc"},
+		{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: []string{"nosuid", "strictatime", "mode=755", "size=1m"}},
+		{Destination: req.Input, Type: "bind", Source: req.Input, Options: []string{"bind", "ro"}},
+	}
+	for _, dir := range rootfsLibDirs(req.Bin) {
+		mounts = append(mounts, specs.Mount{Destination: dir, Type: "bind", Source: dir, Options: []string{"bind", "ro"}})
+	}
+	if req.WorkDir != "" {
+		workDirOpt := "rw"
+		if cfg.ReadOnlyRootfs {
+			workDirOpt = "ro"
+		}
+		mounts = append(mounts, specs.Mount{Destination: req.WorkDir, Type: "bind", Source: req.WorkDir, Options: []string{"bind", workDirOpt}})
+	}
+
+	if err := createRootfsMountpoints(rootfsDir, mounts, req.Input); err != nil {
+		return nil, err
+	}
+
+	namespaces := []specs.LinuxNamespace{
+		{Type: specs.PIDNamespace},
+		{Type: specs.MountNamespace},
+		{Type: specs.IPCNamespace},
+		{Type: specs.UTSNamespace},
+	}
+	if cfg.NoNetwork {
+		namespaces = append(namespaces, specs.LinuxNamespace{Type: specs.NetworkNamespace})
+	}
+
+	var caps *specs.LinuxCapabilities
+	if cfg.DropAllCapabilities {
+		caps = &specs.LinuxCapabilities{}
+	}
+
+	var resources *specs.LinuxResources
+	if cfg.MemoryLimitBytes > 0 || cfg.CPUQuotaMicros > 0 {
+		resources = &specs.LinuxResources{}
+		if cfg.MemoryLimitBytes > 0 {
+			resources.Memory = &specs.LinuxMemory{Limit: &cfg.MemoryLimitBytes}
+		}
+		if cfg.CPUQuotaMicros > 0 {
+			resources.CPU = &specs.LinuxCPU{Quota: &cfg.CPUQuotaMicros}
+		}
+	}
+
+	var oomScoreAdj *int
+	if cfg.OOMScoreAdj != 0 {
+		oomScoreAdj = &cfg.OOMScoreAdj
+	}
+
+	var seccomp *specs.LinuxSeccomp
+	if cfg.Seccomp {
+		seccomp = defaultSeccompProfile()
+	}
+
+	return &specs.Spec{
+		Version: specs.Version,
+		Root:    &specs.Root{Path: rootfsDir, Readonly: true},
+		Process: &specs.Process{
+			Terminal:     false,
+			Args:         append([]string{req.Bin}, req.Args...),
+			Cwd:          "/",
+			Env:          []string{"PATH=/usr/bin:/bin"},
+			OOMScoreAdj:  oomScoreAdj,
+			Capabilities: caps,
+		},
+		Mounts: mounts,
+		Linux: &specs.Linux{
+			Namespaces: namespaces,
+			Resources:  resources,
+			Seccomp:    seccomp,
+		},
+	}, nil
+}
+
+// createRootfsMountpoints creates rootfsDir and, under it, an empty
+// directory or file for every mount destination so runc has somewhere to
+// bind onto – it refuses to bind a source onto a target that doesn't exist.
+func createRootfsMountpoints(rootfsDir string, mounts []specs.Mount, inputPath string) error {
+	if err := os.MkdirAll(rootfsDir, 0o755); err != nil {
+		return fmt.Errorf("creating rootfs dir: %w", err)
+	}
+	for _, m := range mounts {
+		target := filepath.Join(rootfsDir, m.Destination)
+		if m.Destination == inputPath {
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return fmt.Errorf("creating mountpoint parent for %s: %w", m.Destination, err)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0o644)
+			if err != nil {
+				return fmt.Errorf("creating mountpoint for %s: %w", m.Destination, err)
+			}
+			f.Close()
+			continue
+		}
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return fmt.Errorf("creating mountpoint for %s: %w", m.Destination, err)
+		}
+	}
+	return nil
+}
+
+// defaultSeccompProfile denies the syscalls most relevant to sandbox escapes
+// (namespace/mount manipulation, module loading, raw ptrace) and allows
+// everything else – a deny-list rather than the stricter default allow-list
+// container runtimes ship, since mutool's exact syscall footprint isn't
+// enumerated here.
+func defaultSeccompProfile() *specs.LinuxSeccomp {
+	denied := []specs.LinuxSyscall{
+		{
+			Names:  []string{"mount", "umount2", "pivot_root", "init_module", "delete_module", "ptrace", "reboot", "swapon", "swapoff"},
+			Action: specs.ActErrno,
+		},
+	}
+	return &specs.LinuxSeccomp{
+		DefaultAction: specs.ActAllow,
+		Syscalls:      denied,
+	}
+}
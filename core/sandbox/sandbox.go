@@ -0,0 +1,128 @@
+// Package sandbox abstracts away *how* an external subprocess gets isolated
+// so adapters (mupdf, pdfcpu, …) can request sandboxing without linking
+// against any particular container runtime. Three backends are supported,
+// tried in this order unless core.SandboxConfig.Backend pins one:
+//
+//  1. runc/crun – generates an OCI runtime-spec bundle and runs it.
+//  2. bwrap     – bubblewrap, a lighter-weight unprivileged sandbox.
+//  3. exec      – plain os/exec fallback; applies whatever of the requested
+//     limits it can (OOM score) and otherwise runs unisolated.
+//
+// Backend selection mirrors mupdf.discover: PATH lookup with a graceful
+// fallback, since production and CI environments rarely have every runtime
+// installed.
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+// Request describes the subprocess a backend should run.
+type Request struct {
+	Bin    string    // absolute (or PATH-resolved) path to the binary
+	Args   []string  // arguments, excluding argv[0]
+	Input  string    // path to the file the subprocess reads; mounted read-only
+	Stdout io.Writer // subprocess stdout
+	// Stderr is not written to by any backend: each one captures the
+	// subprocess's stderr and includes it in the error it returns.
+	Stderr io.Writer
+
+	// WorkDir is scratch space for the subprocess, bound into the sandbox
+	// (writable unless cfg.ReadOnlyRootfs) by the runc/crun and bwrap
+	// backends. Empty ⇒ no scratch dir is mounted. Backend bookkeeping such
+	// as runc's bundle lives in a separate temp dir, never in WorkDir.
+	WorkDir string
+
+	// Logger receives backend-selection and best-effort-limitation notices.
+	// nil ⇒ slog.Default().
+	Logger *slog.Logger
+}
+
+// backend runs a Request under cfg's constraints.
+type backend interface {
+	run(ctx context.Context, req Request, cfg core.SandboxConfig) error
+}
+
+// Run isolates and executes req according to cfg. It never returns
+// ErrNotFound-style errors for a missing preferred backend unless cfg.Backend
+// pins one explicitly – otherwise it silently falls back to the next
+// candidate, down to the unisolated exec backend.
+func Run(ctx context.Context, req Request, cfg core.SandboxConfig) error {
+	logger := req.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	b, name, err := resolveBackend(cfg)
+	if err != nil {
+		return err
+	}
+	logger.Debug("sandbox: selected backend", "backend", name)
+
+	return b.run(ctx, req, cfg)
+}
+
+// resolveBackend picks a backend per cfg.Backend, or auto-detects one in
+// runc > crun > bwrap > exec preference order when cfg.Backend is empty.
+func resolveBackend(cfg core.SandboxConfig) (backend, string, error) {
+	switch cfg.Backend {
+	case "runc":
+		return newRuncBackend("runc")
+	case "crun":
+		return newRuncBackend("crun")
+	case "bwrap":
+		return newBwrapBackend()
+	case "exec":
+		return execBackend{}, "exec", nil
+	case "":
+		if b, name, err := newRuncBackend("runc"); err == nil {
+			return b, name, nil
+		}
+		if b, name, err := newRuncBackend("crun"); err == nil {
+			return b, name, nil
+		}
+		if b, name, err := newBwrapBackend(); err == nil {
+			return b, name, nil
+		}
+		return execBackend{}, "exec", nil
+	default:
+		return nil, "", fmt.Errorf("sandbox: unknown backend %q", cfg.Backend)
+	}
+}
+
+func lookPath(bin string) (string, error) {
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return "", fmt.Errorf("sandbox: %s not found on PATH: %w", bin, err)
+	}
+	return path, nil
+}
+
+// rootfsLibDirs returns the host directories that must stay visible inside
+// the sandbox for the dynamic linker to resolve bin's shared libraries, plus
+// bin's own directory – the minimal set runc and bwrap both need instead of
+// exposing the whole host root. Only directories that actually exist on the
+// host are returned, since lib64 vs. lib vs. usr/lib varies by distro.
+func rootfsLibDirs(bin string) []string {
+	candidates := []string{"/lib", "/lib64", "/usr/lib", "/usr/lib64", filepath.Dir(bin)}
+	seen := make(map[string]bool, len(candidates))
+	var dirs []string
+	for _, dir := range candidates {
+		if dir == "" || dir == "." || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,55 @@
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+func TestResolveBackendUnknown(t *testing.T) {
+	_, _, err := resolveBackend(core.SandboxConfig{Backend: "not-a-real-backend"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown backend name")
+	}
+}
+
+func TestResolveBackendExecAlwaysAvailable(t *testing.T) {
+	b, name, err := resolveBackend(core.SandboxConfig{Backend: "exec"})
+	if err != nil {
+		t.Fatalf("exec backend should always resolve: %v", err)
+	}
+	if name != "exec" {
+		t.Errorf("expected backend name %q, got %q", "exec", name)
+	}
+	if _, ok := b.(execBackend); !ok {
+		t.Errorf("expected execBackend, got %T", b)
+	}
+}
+
+func TestResolveBackendAutoFallsBackToExec(t *testing.T) {
+	// In a minimal test environment none of runc/crun/bwrap are installed,
+	// so auto-detection should still resolve to the unisolated exec backend
+	// rather than erroring out. Where one of them is installed, it is picked
+	// instead, so the exec check only applies when none is on PATH.
+	b, name, err := resolveBackend(core.SandboxConfig{})
+	if err != nil {
+		t.Fatalf("auto-detect should never fail outright: %v", err)
+	}
+	if name == "" {
+		t.Error("expected a non-empty backend name")
+	}
+	noRuntime := true
+	for _, bin := range []string{"runc", "crun", "bwrap"} {
+		if _, err := lookPath(bin); err == nil {
+			noRuntime = false
+		}
+	}
+	if noRuntime {
+		if name != "exec" {
+			t.Errorf("expected fallback backend %q with no runtime on PATH, got %q", "exec", name)
+		}
+		if _, ok := b.(execBackend); !ok {
+			t.Errorf("expected execBackend with no runtime on PATH, got %T", b)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Package layout classifies stext.json text lines as body text or one of up
+// to three heading levels. It replaces the naive "three largest font sizes"
+// rule with a weighted kernel density estimate over font size, plus a couple
+// of layout signals (bold weight, horizontal centering) that catch headings
+// which happen to share body text's size.
+package layout
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// HeaderLevel is the structural role Classify assigns to a line.
+type HeaderLevel int
+
+const (
+	LevelBody HeaderLevel = iota
+	LevelTitle
+	LevelSection
+	LevelSubsection
+)
+
+// Line is the subset of an stext.json text line Classify needs: font
+// size/weight for the KDE pass, the line's text (for char-count weighting
+// and the long-line demotion), and enough geometry to detect horizontal
+// centering. PageWidth is the caller's best estimate of the page's content
+// width; 0 disables centering promotion for that line.
+type Line struct {
+	Size      float64 // Font.Size
+	Weight    string  // Font.Weight, e.g. "Bold", "Heavy", "Regular"
+	Text      string
+	X         float64 // BBox.X
+	W         float64 // BBox.W
+	PageWidth float64
+}
+
+const (
+	kdeBandwidth       = 0.05 // bandwidth for the KDE over log(size)
+	maxHeaderLevels    = 3
+	maxHeaderChars     = 80   // lines longer than this read as body, not a heading
+	centerTolerancePct = 0.10 // how close to page-center counts as "centered"
+)
+
+// Classifier assigns a HeaderLevel to lines, trained on one document's (or
+// one batch's) Lines via New.
+type Classifier struct {
+	baseline float64   // the body text's dominant size, in points
+	levels   []float64 // header size thresholds, descending: Title, Section, Subsection
+}
+
+// Baseline returns the body-text size the Classifier was trained against.
+func (c *Classifier) Baseline() float64 { return c.baseline }
+
+// Levels returns the header-level size thresholds, descending (Title first).
+func (c *Classifier) Levels() []float64 { return append([]float64(nil), c.levels...) }
+
+// sizeWeight is a weighted-histogram key: a rounded font size plus whether
+// the run is bold/heavy. Keeping the two dimensions separate lets a bold
+// run sitting at the same size as body text form its own density peak
+// instead of being absorbed into the baseline bucket.
+type sizeWeight struct {
+	size int
+	bold bool
+}
+
+// New trains a Classifier from lines:
+//  1. builds a weighted histogram keyed on (rounded size, bold), weighting
+//     each by character count so the highest-volume size – body text –
+//     dominates;
+//  2. takes the heaviest size (summed across both weight buckets) as the
+//     body baseline;
+//  3. runs a 1-D Gaussian KDE over log(size) separately for the regular-
+//     and bold-weight buckets, keeping local maxima strictly above the
+//     baseline from the regular pass, and at-or-above the baseline from
+//     the bold pass (bold is already a heading signal on its own, so a
+//     bold run doesn't need to grow past body size to qualify). Results
+//     are merged, deduped by size, sorted descending, capped at three.
+func New(lines []Line) *Classifier {
+	weights := map[sizeWeight]int{}
+	for _, ln := range lines {
+		text := strings.TrimSpace(ln.Text)
+		if text == "" || ln.Size <= 0 {
+			continue
+		}
+		key := sizeWeight{size: int(math.Round(ln.Size)), bold: isBold(ln.Weight)}
+		weights[key] += len([]rune(text))
+	}
+	if len(weights) == 0 {
+		return &Classifier{}
+	}
+
+	totals := map[int]int{}
+	regular := map[int]int{}
+	bold := map[int]int{}
+	for k, w := range weights {
+		totals[k.size] += w
+		if k.bold {
+			bold[k.size] += w
+		} else {
+			regular[k.size] += w
+		}
+	}
+
+	baseline := float64(heaviestBucket(totals))
+	levels := append(kdePeaksAbove(regular, baseline, false), kdePeaksAbove(bold, baseline, true)...)
+	return &Classifier{
+		baseline: baseline,
+		levels:   dedupeDescending(levels),
+	}
+}
+
+// heaviestBucket returns the rounded size with the greatest total character
+// weight – the body text baseline. Ties favor the smaller size, since body
+// text is rarely the largest thing on a page.
+func heaviestBucket(weights map[int]int) int {
+	best, bestWeight := 0, -1
+	for size, w := range weights {
+		if w > bestWeight || (w == bestWeight && size < best) {
+			best, bestWeight = size, w
+		}
+	}
+	return best
+}
+
+// kdePeaksAbove runs a Gaussian KDE over log(size) weighted by weights, and
+// returns the local maxima with size greater than (or, if inclusive, greater
+// than or equal to) above. A single-sample histogram has no curve to find a
+// local maximum in, so it's reported directly when inclusive and it clears
+// the threshold – the case of a lone bold size coinciding with the baseline.
+func kdePeaksAbove(weights map[int]int, above float64, inclusive bool) []float64 {
+	type sample struct {
+		logSize float64
+		weight  float64
+	}
+	samples := make([]sample, 0, len(weights))
+	minLog, maxLog := math.Inf(1), math.Inf(-1)
+	for size, w := range weights {
+		ls := math.Log(float64(size))
+		samples = append(samples, sample{logSize: ls, weight: float64(w)})
+		minLog = math.Min(minLog, ls)
+		maxLog = math.Max(maxLog, ls)
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	logAbove := math.Log(above)
+	if minLog == maxLog {
+		if inclusive && minLog >= logAbove {
+			return []float64{math.Exp(minLog)}
+		}
+		return nil // a single distinct size – nothing to distinguish a header by
+	}
+
+	const gridPoints = 200
+	density := func(x float64) float64 {
+		var d float64
+		for _, s := range samples {
+			z := (x - s.logSize) / kdeBandwidth
+			d += s.weight * math.Exp(-0.5*z*z)
+		}
+		return d
+	}
+
+	step := (maxLog - minLog) / float64(gridPoints-1)
+	grid := make([]float64, gridPoints)
+	for i := range grid {
+		grid[i] = density(minLog + float64(i)*step)
+	}
+
+	var peaks []float64
+	for i := 0; i < gridPoints; i++ {
+		x := minLog + float64(i)*step
+		if inclusive {
+			if x < logAbove {
+				continue
+			}
+		} else if x <= logAbove {
+			continue
+		}
+		// A grid point is a local maximum if no present neighbor exceeds it;
+		// boundary points (the largest size in the document has no point
+		// past it) are compared against their one neighbor only, so a
+		// genuine peak sitting at the edge of the range still counts.
+		if i > 0 && grid[i-1] > grid[i] {
+			continue
+		}
+		if i < gridPoints-1 && grid[i+1] > grid[i] {
+			continue
+		}
+		peaks = append(peaks, math.Exp(x))
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(peaks)))
+	if len(peaks) > maxHeaderLevels {
+		peaks = peaks[:maxHeaderLevels]
+	}
+	return peaks
+}
+
+// dedupeDescending merges the regular- and bold-weight peak lists, drops
+// duplicate rounded sizes (a size can legitimately appear in both), sorts
+// descending, and caps at maxHeaderLevels.
+func dedupeDescending(peaks []float64) []float64 {
+	seen := make(map[int]bool, len(peaks))
+	merged := make([]float64, 0, len(peaks))
+	for _, p := range peaks {
+		r := int(math.Round(p))
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		merged = append(merged, p)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(merged)))
+	if len(merged) > maxHeaderLevels {
+		merged = merged[:maxHeaderLevels]
+	}
+	return merged
+}
+
+// Classify assigns ln a HeaderLevel:
+//  1. its size is matched against the trained levels, from Title down;
+//  2. failing that, a bold line or one horizontally centered on the page is
+//     still promoted to the weakest trained header level, since headings
+//     sometimes share body text's size;
+//  3. a candidate whose text runs longer than ~80 characters is demoted
+//     back to body – likely a caption or paragraph sharing a header's size,
+//     not an actual heading.
+func (c *Classifier) Classify(ln Line) HeaderLevel {
+	level := c.classifyBySize(ln.Size)
+	if level == LevelBody && len(c.levels) > 0 && (isBold(ln.Weight) || isCentered(ln)) {
+		level = HeaderLevel(len(c.levels))
+	}
+	if level != LevelBody && isLongLine(ln.Text) {
+		level = LevelBody
+	}
+	return level
+}
+
+func (c *Classifier) classifyBySize(size float64) HeaderLevel {
+	for i, threshold := range c.levels {
+		if size >= threshold {
+			return HeaderLevel(i + 1) // levels[0]=Title, levels[1]=Section, levels[2]=Subsection
+		}
+	}
+	return LevelBody
+}
+
+func isBold(weight string) bool {
+	return weight == "Bold" || weight == "Heavy"
+}
+
+func isCentered(ln Line) bool {
+	if ln.PageWidth <= 0 {
+		return false
+	}
+	center := ln.X + ln.W/2
+	return math.Abs(center-ln.PageWidth/2) <= centerTolerancePct*ln.PageWidth
+}
+
+func isLongLine(text string) bool {
+	return len([]rune(strings.TrimSpace(text))) > maxHeaderChars
+}
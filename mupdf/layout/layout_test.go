@@ -0,0 +1,98 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func repeat(text string, n int) string {
+	return strings.Repeat(text+" ", n)
+}
+
+func TestNew_PicksHeaderLevelsAboveBaseline(t *testing.T) {
+	var lines []Line
+	for i := 0; i < 200; i++ {
+		lines = append(lines, Line{Size: 11, Text: repeat("body text", 5)})
+	}
+	for i := 0; i < 5; i++ {
+		lines = append(lines, Line{Size: 24, Text: "Document Title"})
+		lines = append(lines, Line{Size: 16, Text: "Section Heading"})
+	}
+
+	clf := New(lines)
+
+	if clf.Baseline() != 11 {
+		t.Fatalf("Baseline() = %v, want 11", clf.Baseline())
+	}
+	if len(clf.Levels()) == 0 {
+		t.Fatalf("expected at least one header level above the baseline, got none")
+	}
+	if clf.Classify(Line{Size: 11, Text: "ordinary body text"}) != LevelBody {
+		t.Errorf("expected baseline-sized line to classify as body")
+	}
+	if clf.Classify(Line{Size: 24, Text: "Document Title"}) == LevelBody {
+		t.Errorf("expected the largest trained size to classify as a header")
+	}
+}
+
+func TestClassify_PromotesBoldAtBodySize(t *testing.T) {
+	var lines []Line
+	for i := 0; i < 50; i++ {
+		lines = append(lines, Line{Size: 12, Text: repeat("regular paragraph text", 4)})
+	}
+	for i := 0; i < 50; i++ {
+		lines = append(lines, Line{Size: 18, Text: "Heading"})
+	}
+	clf := New(lines)
+
+	if level := clf.Classify(Line{Size: 12, Weight: "Bold", Text: "Inline Bold Label"}); level == LevelBody {
+		t.Errorf("expected a bold body-sized line to be promoted to a header level, got LevelBody")
+	}
+	if level := clf.Classify(Line{Size: 12, Weight: "Regular", Text: "not bold"}); level != LevelBody {
+		t.Errorf("expected a non-bold body-sized line to stay body, got %v", level)
+	}
+}
+
+func TestClassify_PromotesCenteredAtBodySize(t *testing.T) {
+	var lines []Line
+	for i := 0; i < 50; i++ {
+		lines = append(lines, Line{Size: 12, Text: repeat("regular paragraph text", 4)})
+	}
+	for i := 0; i < 50; i++ {
+		lines = append(lines, Line{Size: 18, Text: "Heading"})
+	}
+	clf := New(lines)
+
+	centered := Line{Size: 12, Text: "Centered Caption", X: 280, W: 40, PageWidth: 612}
+	if level := clf.Classify(centered); level == LevelBody {
+		t.Errorf("expected a centered body-sized line to be promoted to a header level, got LevelBody")
+	}
+
+	offCenter := Line{Size: 12, Text: "Left Aligned", X: 10, W: 40, PageWidth: 612}
+	if level := clf.Classify(offCenter); level != LevelBody {
+		t.Errorf("expected an off-center body-sized line to stay body, got %v", level)
+	}
+}
+
+func TestClassify_DemotesLongLines(t *testing.T) {
+	var lines []Line
+	for i := 0; i < 50; i++ {
+		lines = append(lines, Line{Size: 12, Text: repeat("regular paragraph text", 4)})
+	}
+	for i := 0; i < 50; i++ {
+		lines = append(lines, Line{Size: 18, Text: "Heading"})
+	}
+	clf := New(lines)
+
+	longLine := Line{Size: 18, Text: strings.Repeat("word ", 30)} // > 80 chars
+	if level := clf.Classify(longLine); level != LevelBody {
+		t.Errorf("expected a long header-sized line to be demoted to body, got %v", level)
+	}
+}
+
+func TestNew_EmptyInput(t *testing.T) {
+	clf := New(nil)
+	if got := clf.Classify(Line{Size: 12, Text: "anything"}); got != LevelBody {
+		t.Errorf("expected an untrained classifier to default to LevelBody, got %v", got)
+	}
+}
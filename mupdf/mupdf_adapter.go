@@ -20,6 +20,9 @@ import (
 	"syscall"
 
 	"github.com/SaiNageswarS/gizmo/core"
+	"github.com/SaiNageswarS/gizmo/core/cache"
+	"github.com/SaiNageswarS/gizmo/core/sandbox"
+	"github.com/SaiNageswarS/gizmo/mupdf/layout"
 )
 
 // public aliases for registry keys
@@ -102,10 +105,11 @@ func (p *processor) Do(ctx context.Context, in io.Reader, out io.Writer, opts ..
 		return err
 	}
 	cfg := core.BuildConfig(opts...)
-	fileIn, ok := in.(*os.File)
-	if !ok {
-		return fmt.Errorf("mupdf: input must be a *os.File (got %T)", in)
+	inputPath, cleanup, err := spoolToFile(cfg, in)
+	if err != nil {
+		return fmt.Errorf("mupdf: spooling input: %w", err)
 	}
+	defer cleanup()
 
 	args := append([]string{p.mode}, p.args...)
 	if len(cfg.Pages) > 0 {
@@ -118,9 +122,21 @@ func (p *processor) Do(ctx context.Context, in io.Reader, out io.Writer, opts ..
 		args = append(args, "-r", fmt.Sprint(dpi))
 	}
 	args = append(args, "-o", "-")
-	args = append(args, fileIn.Name())
+	args = append(args, inputPath)
 
 	log.Printf("mupdf: running %s %s\n", binPath, strings.Join(args, " "))
+
+	if sb, ok := cfg.Extra["sandbox"].(core.SandboxConfig); ok {
+		return sandbox.Run(ctx, sandbox.Request{
+			Bin:     binPath,
+			Args:    args,
+			Input:   inputPath,
+			Stdout:  out,
+			WorkDir: cfg.WorkDir,
+			Logger:  cfg.Logger,
+		}, sb)
+	}
+
 	cmd := exec.CommandContext(ctx, binPath, args...)
 	cmd.Stdout = out
 	var stderr bytes.Buffer
@@ -132,6 +148,45 @@ func (p *processor) Do(ctx context.Context, in io.Reader, out io.Writer, opts ..
 	return nil
 }
 
+// spoolToFile returns a real on-disk path backing r's contents so the mutool
+// CLI can operate on it. If r is already a genuine *os.File (the common case
+// when cfg.FS is core.OSFS), its existing path is reused and cleanup is a
+// no-op. Otherwise – a memfs handle, an S3-backed reader, or any other
+// non-*os.File – r is spooled into a temp file under cfg.WorkDir, which the
+// caller must remove via the returned cleanup func.
+func spoolToFile(cfg *core.Config, r io.Reader) (path string, cleanup func(), err error) {
+	if f, ok := r.(*os.File); ok {
+		return f.Name(), func() {}, nil
+	}
+
+	tmp, err := os.CreateTemp(cfg.WorkDir, "gizmo-mupdf-*.pdf")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, err
+	}
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
+
+// resolveFile opens path through cfg.FS and returns a real on-disk path the
+// mutool CLI can be pointed at directly, spooling via spoolToFile when the
+// FS-provided handle isn't a genuine *os.File.
+func resolveFile(cfg *core.Config, path string) (string, func(), error) {
+	f, err := cfg.FS.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	return spoolToFile(cfg, f)
+}
+
 func intsToPageSpec(pages []int) string {
 	if len(pages) == 1 {
 		return fmt.Sprint(pages[0])
@@ -143,6 +198,20 @@ func intsToPageSpec(pages []int) string {
 	return strings.Join(ss, ",")
 }
 
+// WithLegacyClassifier opts ExtractStructuredText, ExtractStructuredTextStream
+// and ClassifyPages back into the pre-KDE header classifier, which simply
+// ranks the three largest rounded font sizes as Title/Section/Subsection.
+// Kept for regression safety while the mupdf/layout-based classifier (the
+// default) bakes in.
+func WithLegacyClassifier() core.Option {
+	return core.WithExtra("legacyClassifier", true)
+}
+
+func isLegacyClassifier(cfg *core.Config) bool {
+	legacy, _ := cfg.Extra["legacyClassifier"].(bool)
+	return legacy
+}
+
 // Registration helpers -----------------------------------------------------------
 
 func init() {
@@ -165,28 +234,65 @@ func NewOutlineExtractor() core.Processor {
 
 // Convenience wrappers -----------------------------------------------------------
 
-func ExtractTextFile(ctx context.Context, src, dst string, pages ...int) error {
-	in, err := os.Open(src)
+func ExtractTextFile(ctx context.Context, src, dst string, opts ...core.Option) error {
+	cfg := core.BuildConfig(opts...)
+
+	in, err := cfg.FS.Open(src)
 	if err != nil {
 		return err
 	}
 	defer in.Close()
 
-	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+	if err := cfg.FS.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
 		return err
 	}
-	out, err := os.Create(dst)
+	out, err := cfg.FS.Create(dst)
 	if err != nil {
 		return err
 	}
 	defer out.Close()
 
 	proc := NewTextExtractor()
-	return proc.Do(ctx, in, out, core.WithPages(pages...))
+	return proc.Do(ctx, in, out, opts...)
 }
 
-func ExtractText(ctx context.Context, path string, pages ...int) (string, error) {
-	f, err := os.Open(path)
+// resultCache backs the WithCache(true) option for ExtractText,
+// GetPageCount and ExtractStructuredText. See core/cache for eviction
+// policy (LRU plus memory-pressure based).
+var resultCache = cache.New()
+
+// cacheKey hashes path's contents (read through cfg.FS) and cfg's
+// cache-relevant fields into a single lookup key for mode (e.g. "text",
+// "pagecount", "structured").
+func cacheKey(cfg *core.Config, path, mode string) (string, error) {
+	f, err := cfg.FS.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fileHash, err := cache.HashFile(f)
+	if err != nil {
+		return "", err
+	}
+	return cache.Key(fileHash, mode, cache.HashOptions(cfg)), nil
+}
+
+func ExtractText(ctx context.Context, path string, opts ...core.Option) (string, error) {
+	cfg := core.BuildConfig(opts...)
+
+	var key string
+	if cfg.Cache {
+		var err error
+		if key, err = cacheKey(cfg, path, "text"); err == nil {
+			if v, ok := resultCache.Get(key); ok {
+				cfg.Logger.Debug("mupdf: cache hit", "mode", "text", "path", path)
+				return v.(string), nil
+			}
+		}
+	}
+
+	f, err := cfg.FS.Open(path)
 	if err != nil {
 		return "", err
 	}
@@ -194,17 +300,39 @@ func ExtractText(ctx context.Context, path string, pages ...int) (string, error)
 
 	var buf bytes.Buffer
 	proc := NewTextExtractor()
-	err = proc.Do(ctx, f, &buf, core.WithPages(pages...))
+	err = proc.Do(ctx, f, &buf, opts...)
+	if err == nil && cfg.Cache && key != "" {
+		resultCache.Put(key, buf.String(), int64(buf.Len()))
+	}
 	return buf.String(), err
 }
 
 // GetPageCount returns the total number of pages in the PDF file.
-func GetPageCount(ctx context.Context, path string) (int, error) {
+func GetPageCount(ctx context.Context, path string, opts ...core.Option) (int, error) {
+	cfg := core.BuildConfig(opts...)
+
+	var key string
+	if cfg.Cache {
+		var err error
+		if key, err = cacheKey(cfg, path, "pagecount"); err == nil {
+			if v, ok := resultCache.Get(key); ok {
+				cfg.Logger.Debug("mupdf: cache hit", "mode", "pagecount", "path", path)
+				return v.(int), nil
+			}
+		}
+	}
+
 	if _, err := discover(); err != nil {
 		return 0, err
 	}
 
-	cmd := exec.CommandContext(ctx, binPath, "info", path)
+	realPath, cleanup, err := resolveFile(cfg, path)
+	if err != nil {
+		return 0, err
+	}
+	defer cleanup()
+
+	cmd := exec.CommandContext(ctx, binPath, "info", realPath)
 	out, err := cmd.Output()
 	if err != nil {
 		return 0, fmt.Errorf("mupdf info error: %w", err)
@@ -213,7 +341,11 @@ func GetPageCount(ctx context.Context, path string) (int, error) {
 		if strings.HasPrefix(line, "Pages:") {
 			parts := strings.Fields(line)
 			if len(parts) == 2 {
-				return strconv.Atoi(parts[1])
+				n, err := strconv.Atoi(parts[1])
+				if err == nil && cfg.Cache && key != "" {
+					resultCache.Put(key, n, 8)
+				}
+				return n, err
 			}
 		}
 	}
@@ -244,162 +376,423 @@ type StructuredBlock struct {
 //
 //  1. Pass-1: build a histogram of rounded font sizes.
 //  2. Pass-2: re-walk pages, classify headers/body, and aggregate paragraphs.
-func ExtractStructuredText(ctx context.Context, pdfPath string) ([]StructuredBlock, error) {
+//
+// Pass WithCache(true) to reuse a previous call's result (keyed on file
+// hash + options) instead of reparsing, via core/cache.
+func ExtractStructuredText(ctx context.Context, pdfPath string, opts ...core.Option) ([]StructuredBlock, error) {
+	cfg := core.BuildConfig(opts...)
+
+	var key string
+	if cfg.Cache {
+		var err error
+		if key, err = cacheKey(cfg, pdfPath, "structured"); err == nil {
+			if v, ok := resultCache.Get(key); ok {
+				cfg.Logger.Debug("mupdf: cache hit", "mode", "structured", "path", pdfPath)
+				return v.([]StructuredBlock), nil
+			}
+		}
+	}
+
 	bin, err := discover()
 	if err != nil {
 		return nil, fmt.Errorf("mupdf binary not found: %w", err)
 	}
 
+	realPath, cleanup, err := resolveFile(cfg, pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	pageCount, pc, err := structuredTextPrelude(ctx, cfg, bin, realPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// ---------------------------------------------------------------------
-	// Helper: how many pages does the PDF have?
+	// PASS-2  ▸ build structured blocks
 	// ---------------------------------------------------------------------
-	pageCount, err := GetPageCount(ctx, pdfPath)
+	var blocks []StructuredBlock
+	emit := func(b StructuredBlock) { blocks = append(blocks, b) }
+
+	for start := 1; start <= pageCount; start += batchSize {
+		end := min(start+batchSize-1, pageCount)
+		pages, err := loadPagesBatch(ctx, bin, realPath, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("pass-2 (pages %d-%d): %w", start, end, err)
+		}
+
+		for i, page := range pages {
+			pc.feedPage(page, start+i, emit)
+		}
+		reportProgress(cfg, end, pageCount)
+	}
+	pc.flush(emit)
+
+	if cfg.Cache && key != "" {
+		size := int64(0)
+		for _, b := range blocks {
+			size += int64(len(b.Text) + len(b.HeaderHierarchy))
+		}
+		resultCache.Put(key, blocks, size)
+	}
+
+	return blocks, nil
+}
+
+// StructuredBlockResult wraps a single StructuredBlock emitted by
+// ExtractStructuredTextStream, or the terminal error that ended the stream.
+type StructuredBlockResult struct {
+	Block StructuredBlock
+	Err   error
+}
+
+// ExtractStructuredTextStream mirrors ExtractStructuredText's two-pass
+// classification, but yields blocks incrementally over the returned channel
+// as pass-2 produces them (after pass-1's font histogram completes), rather
+// than accumulating them all in memory. The channel is closed after the
+// last block or a terminal error; cancel ctx to stop early.
+func ExtractStructuredTextStream(ctx context.Context, pdfPath string, opts ...core.Option) (<-chan StructuredBlockResult, error) {
+	cfg := core.BuildConfig(opts...)
+
+	bin, err := discover()
+	if err != nil {
+		return nil, fmt.Errorf("mupdf binary not found: %w", err)
+	}
+
+	realPath, cleanup, err := resolveFile(cfg, pdfPath)
 	if err != nil {
 		return nil, err
 	}
+
+	pageCount, pc, err := structuredTextPrelude(ctx, cfg, bin, realPath)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	results := make(chan StructuredBlockResult)
+
+	go func() {
+		defer close(results)
+		defer cleanup()
+
+		send := func(r StructuredBlockResult) bool {
+			select {
+			case results <- r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		emit := func(b StructuredBlock) { send(StructuredBlockResult{Block: b}) }
+
+		for start := 1; start <= pageCount; start += batchSize {
+			if ctx.Err() != nil {
+				send(StructuredBlockResult{Err: ctx.Err()})
+				return
+			}
+			end := min(start+batchSize-1, pageCount)
+			pages, err := loadPagesBatch(ctx, bin, realPath, start, end)
+			if err != nil {
+				send(StructuredBlockResult{Err: fmt.Errorf("pass-2 (pages %d-%d): %w", start, end, err)})
+				return
+			}
+			for i, page := range pages {
+				pc.feedPage(page, start+i, emit)
+			}
+			reportProgress(cfg, end, pageCount)
+		}
+		pc.flush(emit)
+	}()
+
+	return results, nil
+}
+
+// structuredTextPrelude runs pass-1 (page count + header-classifier
+// training) shared by ExtractStructuredText and ExtractStructuredTextStream,
+// reporting progress via cfg.Logger and cfg.ProgressCallback instead of
+// fmt.Printf. realPath must already be a real on-disk path (see
+// resolveFile), since it's handed straight to GetPageCount with no FS
+// option, and to the mutool CLI. The returned *pageClassifier is trained by
+// the legacy top-three-font-size rule if cfg carries WithLegacyClassifier(),
+// or by mupdf/layout's KDE-based classifier otherwise.
+func structuredTextPrelude(ctx context.Context, cfg *core.Config, bin, realPath string) (pageCount int, pc *pageClassifier, err error) {
+	pageCount, err = GetPageCount(ctx, realPath)
+	if err != nil {
+		return 0, nil, err
+	}
 	if pageCount == 0 {
-		return nil, fmt.Errorf("empty PDF or page count undetected")
+		return 0, nil, fmt.Errorf("empty PDF or page count undetected")
 	}
 
-	fmt.Printf("Processing %d pages in %s\n", pageCount, pdfPath)
-	// ---------------------------------------------------------------------
-	// PASS-1  ▸ font histogram
-	// ---------------------------------------------------------------------
+	cfg.Logger.Info("mupdf: processing PDF", "pages", pageCount, "path", realPath)
+
+	if isLegacyClassifier(cfg) {
+		pc, err = legacyPageClassifierFromPages(ctx, cfg, bin, realPath, pageCount)
+	} else {
+		pc, err = layoutPageClassifierFromPages(ctx, cfg, bin, realPath, pageCount)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageCount, pc, nil
+}
+
+// legacyPageClassifierFromPages runs pass-1 with the pre-KDE rule: rank the
+// three largest rounded font sizes as Title/Section/Subsection.
+func legacyPageClassifierFromPages(ctx context.Context, cfg *core.Config, bin, realPath string, pageCount int) (*pageClassifier, error) {
 	freq := map[int]int{} // rounded font-size → occurrences
 	for start := 1; start <= pageCount; start += batchSize {
 		end := min(start+batchSize-1, pageCount)
-		pages, err := loadPagesBatch(ctx, bin, pdfPath, start, end)
+		pages, err := loadPagesBatch(ctx, bin, realPath, start, end)
 		if err != nil {
 			return nil, fmt.Errorf("pass-1 (pages %d-%d): %w", start, end, err)
 		}
 		for _, page := range pages {
 			updateFontFreq(freq, page)
 		}
-
-		fmt.Printf("Pass 1: Processed pages %d-%d\n", start, end)
+		reportProgress(cfg, end, pageCount)
 	}
 
 	titleSize, sectionSize, subSize, err := thresholdsFromFreq(freq)
 	if err != nil {
 		return nil, err
 	}
+	cfg.Logger.Info("mupdf: classification thresholds (legacy)",
+		"title", titleSize, "section", sectionSize, "subsection", subSize)
+	return newLegacyPageClassifier(titleSize, sectionSize, subSize), nil
+}
 
-	// ---------------------------------------------------------------------
-	// PASS-2  ▸ build structured blocks
-	// ---------------------------------------------------------------------
-	classify := func(sz float64) string {
-		switch {
-		case titleSize > 0 && sz >= titleSize:
-			return "Title"
-		case sectionSize > 0 && sz >= sectionSize:
-			return "Section"
-		case subSize > 0 && sz >= subSize:
-			return "Subsection"
-		default:
-			return ""
+// layoutPageClassifierFromPages runs pass-1 by training a mupdf/layout
+// Classifier over every text line in the document (see layout.New).
+func layoutPageClassifierFromPages(ctx context.Context, cfg *core.Config, bin, realPath string, pageCount int) (*pageClassifier, error) {
+	var lines []layout.Line
+	for start := 1; start <= pageCount; start += batchSize {
+		end := min(start+batchSize-1, pageCount)
+		pages, err := loadPagesBatch(ctx, bin, realPath, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("pass-1 (pages %d-%d): %w", start, end, err)
+		}
+		for _, page := range pages {
+			lines = append(lines, pageLines(page)...)
 		}
+		reportProgress(cfg, end, pageCount)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no text detected in PDF")
 	}
 
-	var (
-		curTitle, curSection, curSubsection string
-		aggHierarchy                        string
-		aggPage                             int
-		aggBuilder                          strings.Builder
-		blocks                              []StructuredBlock
-	)
+	clf := layout.New(lines)
+	cfg.Logger.Info("mupdf: layout classifier trained",
+		"baseline", clf.Baseline(), "levels", clf.Levels())
+	return newLayoutPageClassifier(clf), nil
+}
 
-	flush := func() {
-		if aggBuilder.Len() == 0 {
-			return
+// reportProgress logs progress at debug level and, if set, invokes
+// cfg.ProgressCallback(done, total).
+func reportProgress(cfg *core.Config, done, total int) {
+	cfg.Logger.Debug("mupdf: progress", "done", done, "total", total)
+	if cfg.ProgressCallback != nil {
+		cfg.ProgressCallback(done, total)
+	}
+}
+
+// pageClassifier holds the running header stack and aggregated body text
+// used by ExtractStructuredText's pass-2, factored out so both the
+// in-memory and streaming entry points share one implementation. roleOf
+// decides a text block's structural role ("Title", "Section", "Subsection",
+// or "" for body) – newLegacyPageClassifier and newLayoutPageClassifier wire
+// it to the two interchangeable classification strategies.
+type pageClassifier struct {
+	roleOf                              func(blk Block, pageWidth float64) string
+	curTitle, curSection, curSubsection string
+	aggHierarchy                        string
+	aggPage                             int
+	aggBuilder                          strings.Builder
+}
+
+// newLegacyPageClassifier ranks blocks purely by the three largest rounded
+// font sizes in the document (see thresholdsFromFreq).
+func newLegacyPageClassifier(titleSize, sectionSize, subSize float64) *pageClassifier {
+	return &pageClassifier{roleOf: func(blk Block, _ float64) string {
+		return legacyClassifySize(blockMaxFontSize(blk), titleSize, sectionSize, subSize)
+	}}
+}
+
+func legacyClassifySize(sz, titleSize, sectionSize, subSize float64) string {
+	switch {
+	case titleSize > 0 && sz >= titleSize:
+		return "Title"
+	case sectionSize > 0 && sz >= sectionSize:
+		return "Section"
+	case subSize > 0 && sz >= subSize:
+		return "Subsection"
+	default:
+		return ""
+	}
+}
+
+// newLayoutPageClassifier ranks blocks with a trained mupdf/layout
+// Classifier, using the block's largest-font line as the representative
+// line for size/weight/geometry.
+func newLayoutPageClassifier(clf *layout.Classifier) *pageClassifier {
+	return &pageClassifier{roleOf: func(blk Block, pageWidth float64) string {
+		ln, ok := blockMaxFontLine(blk)
+		if !ok {
+			return ""
 		}
-		blocks = append(blocks, StructuredBlock{
-			HeaderHierarchy: aggHierarchy,
-			Text:            strings.TrimSpace(aggBuilder.String()),
-			PageNumber:      aggPage,
-		})
-		aggBuilder.Reset()
+		return headerLevelRole(clf.Classify(layout.Line{
+			Size:      ln.Font.Size,
+			Weight:    ln.Font.Weight,
+			Text:      ln.Text,
+			X:         ln.BBox.X,
+			W:         ln.BBox.W,
+			PageWidth: pageWidth,
+		}))
+	}}
+}
+
+func headerLevelRole(level layout.HeaderLevel) string {
+	switch level {
+	case layout.LevelTitle:
+		return "Title"
+	case layout.LevelSection:
+		return "Section"
+	case layout.LevelSubsection:
+		return "Subsection"
+	default:
+		return ""
 	}
+}
 
-	buildHierarchy := func() string {
-		var parts []string
-		if curTitle != "" {
-			parts = append(parts, curTitle)
+// blockMaxFontLine returns the line with the largest Font.Size in blk, or
+// ok=false if blk has no lines.
+func blockMaxFontLine(blk Block) (Line, bool) {
+	var best Line
+	found := false
+	for _, ln := range blk.Lines {
+		if !found || ln.Font.Size > best.Font.Size {
+			best, found = ln, true
 		}
-		if curSection != "" {
-			parts = append(parts, curSection)
+	}
+	return best, found
+}
+
+func blockMaxFontSize(blk Block) float64 {
+	ln, ok := blockMaxFontLine(blk)
+	if !ok {
+		return 0
+	}
+	return ln.Font.Size
+}
+
+// pageWidth estimates page's content width as the widest right edge among
+// its text blocks, for the layout classifier's centering check. Returns 0
+// (centering disabled) if the page has no text blocks.
+func pageWidth(page Page) float64 {
+	var w float64
+	for _, blk := range page.Blocks {
+		if blk.Type != "text" {
+			continue
 		}
-		if curSubsection != "" {
-			parts = append(parts, curSubsection)
+		if right := blk.BBox.X + blk.BBox.W; right > w {
+			w = right
 		}
-		return strings.Join(parts, " | ")
 	}
+	return w
+}
 
-	fmt.Printf("Pass 2: Classifying text blocks with thresholds: Title=%.1f, Section=%.1f, Subsection=%.1f\n",
-		titleSize, sectionSize, subSize)
-
-	for start := 1; start <= pageCount; start += batchSize {
-		end := min(start+batchSize-1, pageCount)
-		pages, err := loadPagesBatch(ctx, bin, pdfPath, start, end)
-		if err != nil {
-			return nil, fmt.Errorf("pass-2 (pages %d-%d): %w", start, end, err)
+// pageLines flattens every text line on page into layout.Line samples for
+// training a layout.Classifier (see layoutPageClassifierFromPages).
+func pageLines(page Page) []layout.Line {
+	var lines []layout.Line
+	for _, blk := range page.Blocks {
+		if blk.Type != "text" {
+			continue
+		}
+		for _, ln := range blk.Lines {
+			lines = append(lines, layout.Line{Size: ln.Font.Size, Weight: ln.Font.Weight, Text: ln.Text})
 		}
+	}
+	return lines
+}
 
-		for i, page := range pages {
-			for _, blk := range page.Blocks {
-				if blk.Type != "text" {
-					continue
-				}
-				var maxSize float64
-				var lineBuilder strings.Builder
-				for _, ln := range blk.Lines {
-					if ln.Font.Size > maxSize {
-						maxSize = ln.Font.Size
-					}
-					t := strings.TrimSpace(ln.Text)
-					if t != "" {
-						lineBuilder.WriteString(t)
-						lineBuilder.WriteString(" ")
-					}
-				}
-				text := strings.TrimSpace(lineBuilder.String())
-				if text == "" {
-					continue
-				}
+func (pc *pageClassifier) buildHierarchy() string {
+	var parts []string
+	if pc.curTitle != "" {
+		parts = append(parts, pc.curTitle)
+	}
+	if pc.curSection != "" {
+		parts = append(parts, pc.curSection)
+	}
+	if pc.curSubsection != "" {
+		parts = append(parts, pc.curSubsection)
+	}
+	return strings.Join(parts, " | ")
+}
 
-				switch classify(maxSize) {
-				case "Title":
-					flush()
-					curTitle, curSection, curSubsection = text, "", ""
-				case "Section":
-					flush()
-					curSection, curSubsection = text, ""
-				case "Subsection":
-					flush()
-					curSubsection = text
-				default: // body
-					h := buildHierarchy()
-					if h == "" {
-						continue // body before any header
-					}
-					if h != aggHierarchy {
-						flush()
-						aggHierarchy = h
-						aggPage = start + i
-					}
-					if aggBuilder.Len() > 0 {
-						aggBuilder.WriteString("\n\n")
-					}
-					aggBuilder.WriteString(text)
-				}
+// flush emits the in-progress aggregated block, if any, via emit.
+func (pc *pageClassifier) flush(emit func(StructuredBlock)) {
+	if pc.aggBuilder.Len() == 0 {
+		return
+	}
+	emit(StructuredBlock{
+		HeaderHierarchy: pc.aggHierarchy,
+		Text:            strings.TrimSpace(pc.aggBuilder.String()),
+		PageNumber:      pc.aggPage,
+	})
+	pc.aggBuilder.Reset()
+}
+
+// feedPage classifies every text block on page (1-based pageNum), emitting
+// completed body-text aggregates via emit as header boundaries are crossed.
+func (pc *pageClassifier) feedPage(page Page, pageNum int, emit func(StructuredBlock)) {
+	pw := pageWidth(page)
+	for _, blk := range page.Blocks {
+		if blk.Type != "text" {
+			continue
+		}
+		var lineBuilder strings.Builder
+		for _, ln := range blk.Lines {
+			t := strings.TrimSpace(ln.Text)
+			if t != "" {
+				lineBuilder.WriteString(t)
+				lineBuilder.WriteString(" ")
 			}
 		}
+		text := strings.TrimSpace(lineBuilder.String())
+		if text == "" {
+			continue
+		}
 
-		fmt.Printf("Pass 2: Processed pages %d-%d\n", start, end)
+		switch pc.roleOf(blk, pw) {
+		case "Title":
+			pc.flush(emit)
+			pc.curTitle, pc.curSection, pc.curSubsection = text, "", ""
+		case "Section":
+			pc.flush(emit)
+			pc.curSection, pc.curSubsection = text, ""
+		case "Subsection":
+			pc.flush(emit)
+			pc.curSubsection = text
+		default: // body
+			h := pc.buildHierarchy()
+			if h == "" {
+				continue // body before any header
+			}
+			if h != pc.aggHierarchy {
+				pc.flush(emit)
+				pc.aggHierarchy = h
+				pc.aggPage = pageNum
+			}
+			if pc.aggBuilder.Len() > 0 {
+				pc.aggBuilder.WriteString("\n\n")
+			}
+			pc.aggBuilder.WriteString(text)
+		}
 	}
-
-	flush()
-
-	return blocks, nil
 }
 
 // loadPage runs mutool draw for a batch of pages and returns it as []Page.
@@ -423,6 +816,46 @@ func loadPagesBatch(ctx context.Context, bin, pdfPath string, from, to int) ([]P
 	return doc.Pages, nil
 }
 
+// ClassifyPages runs the same header/body classification as
+// ExtractStructuredText's pass-2, but over an already in-memory slice of
+// pages rather than streaming batches from mutool. It exists so alternate
+// extraction front-ends (e.g. mupdfcgo, which decodes stext.json straight
+// from libmupdf) can reuse gizmo's classification rules without shelling
+// out at all. Pass WithLegacyClassifier() to opt into the pre-KDE
+// top-three-font-size rule instead of the default mupdf/layout classifier.
+func ClassifyPages(pages []Page, opts ...core.Option) []StructuredBlock {
+	cfg := core.BuildConfig(opts...)
+
+	var pc *pageClassifier
+	if isLegacyClassifier(cfg) {
+		freq := map[int]int{}
+		for _, page := range pages {
+			updateFontFreq(freq, page)
+		}
+		titleSize, sectionSize, subSize, err := thresholdsFromFreq(freq)
+		if err != nil {
+			return nil
+		}
+		pc = newLegacyPageClassifier(titleSize, sectionSize, subSize)
+	} else {
+		var lines []layout.Line
+		for _, page := range pages {
+			lines = append(lines, pageLines(page)...)
+		}
+		pc = newLayoutPageClassifier(layout.New(lines))
+	}
+
+	var blocks []StructuredBlock
+	emit := func(b StructuredBlock) { blocks = append(blocks, b) }
+
+	for i, page := range pages {
+		pc.feedPage(page, i+1, emit)
+	}
+	pc.flush(emit)
+
+	return blocks
+}
+
 // updateFontFreq updates histogram with all line sizes in the given page.
 func updateFontFreq(freq map[int]int, page Page) {
 	const eps = 0.5
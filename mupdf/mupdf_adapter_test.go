@@ -8,6 +8,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/SaiNageswarS/gizmo/core"
+	"github.com/SaiNageswarS/gizmo/core/memfs"
 )
 
 const fixturePDF = "../testdata/SaiNageswarS_Resume.pdf"
@@ -17,7 +20,7 @@ func TestExtractTextFile(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	err := ExtractTextFile(ctx, fixturePDF, tmp, 1)
+	err := ExtractTextFile(ctx, fixturePDF, tmp, core.WithPages(1))
 	if err != nil {
 		t.Fatalf("ExtractTextFile failed: %v", err)
 	}
@@ -38,7 +41,7 @@ func TestExtractText(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	text, err := ExtractText(ctx, fixturePDF, 1)
+	text, err := ExtractText(ctx, fixturePDF, core.WithPages(1))
 	if err != nil {
 		t.Fatalf("ExtractTextFile failed: %v", err)
 	}
@@ -51,6 +54,24 @@ func TestExtractText(t *testing.T) {
 	}
 }
 
+func TestExtractTextCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	before := resultCache.Stats().Hits
+
+	if _, err := ExtractText(ctx, fixturePDF, core.WithPages(1), core.WithCache(true)); err != nil {
+		t.Fatalf("ExtractText (populate) failed: %v", err)
+	}
+	if _, err := ExtractText(ctx, fixturePDF, core.WithPages(1), core.WithCache(true)); err != nil {
+		t.Fatalf("ExtractText (cached) failed: %v", err)
+	}
+
+	if after := resultCache.Stats().Hits; after <= before {
+		t.Errorf("expected a cache hit on the second call, hits went from %d to %d", before, after)
+	}
+}
+
 func TestExtractStructuredText(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -80,6 +101,37 @@ func TestGetPageCount(t *testing.T) {
 	}
 }
 
+func TestExtractStructuredTextStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var progressCalls int
+	ch, err := ExtractStructuredTextStream(ctx, fixturePDF, core.WithProgressCallback(func(done, total int) {
+		progressCalls++
+	}))
+	if err != nil {
+		t.Fatalf("ExtractStructuredTextStream failed: %v", err)
+	}
+
+	var blocks []StructuredBlock
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatalf("stream returned error: %v", r.Err)
+		}
+		blocks = append(blocks, r.Block)
+	}
+
+	if len(blocks) == 0 {
+		t.Fatal("expected non-empty streamed structured text output")
+	}
+	if blocks[0].HeaderHierarchy == "" || blocks[0].Text == "" {
+		t.Error("expected non-empty header hierarchy and text in streamed block")
+	}
+	if progressCalls == 0 {
+		t.Error("expected ProgressCallback to be invoked at least once")
+	}
+}
+
 func TestTextExtractor_Do(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -98,3 +150,111 @@ func TestTextExtractor_Do(t *testing.T) {
 		t.Error("expected some text output")
 	}
 }
+
+func TestSpoolToFile_RealOSFile(t *testing.T) {
+	f, err := os.Open(fixturePDF)
+	if err != nil {
+		t.Skipf("fixture PDF unavailable: %v", err)
+	}
+	defer f.Close()
+
+	cfg := core.BuildConfig()
+	path, cleanup, err := spoolToFile(cfg, f)
+	if err != nil {
+		t.Fatalf("spoolToFile failed: %v", err)
+	}
+	defer cleanup()
+
+	if path != f.Name() {
+		t.Errorf("expected spoolToFile to reuse the *os.File's own path, got %q", path)
+	}
+}
+
+func TestSpoolToFile_NonOSFile(t *testing.T) {
+	cfg := core.BuildConfig()
+	r := bytes.NewReader([]byte("%PDF-fake"))
+	path, cleanup, err := spoolToFile(cfg, r)
+	if err != nil {
+		t.Fatalf("spoolToFile failed: %v", err)
+	}
+	defer cleanup()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read spooled file: %v", err)
+	}
+	if string(b) != "%PDF-fake" {
+		t.Errorf("spooled file contents = %q, want %q", b, "%PDF-fake")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("spooled file missing before cleanup: %v", err)
+	}
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected cleanup to remove the spooled temp file, stat err = %v", err)
+	}
+}
+
+func TestResolveFile_MemFS(t *testing.T) {
+	content := []byte("in-memory pdf bytes")
+	fsys := memfs.New(map[string][]byte{"doc.pdf": content})
+	cfg := core.BuildConfig(core.WithFS(fsys))
+
+	path, cleanup, err := resolveFile(cfg, "doc.pdf")
+	if err != nil {
+		t.Fatalf("resolveFile failed: %v", err)
+	}
+	defer cleanup()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read resolved file: %v", err)
+	}
+	if string(b) != string(content) {
+		t.Errorf("resolved file contents = %q, want %q", b, content)
+	}
+}
+
+func textLine(size float64, weight, text string) Line {
+	return Line{Font: Font{Size: size, Weight: weight}, Text: text}
+}
+
+// synthPages builds a two-page document in-memory: a title, a section
+// heading, and some body text repeated enough that the layout classifier's
+// weighting picks body text as the baseline.
+func synthPages() []Page {
+	bodyBlock := func(text string) Block {
+		return Block{Type: "text", Lines: []Line{textLine(11, "Regular", text)}}
+	}
+	page1 := Page{Blocks: []Block{
+		{Type: "text", Lines: []Line{textLine(24, "Bold", "Gizmo User Guide")}},
+		{Type: "text", Lines: []Line{textLine(16, "Bold", "Introduction")}},
+		bodyBlock("This is the first paragraph of body text under the introduction."),
+		bodyBlock("This is a second paragraph that continues the same section."),
+	}}
+	page2 := Page{Blocks: []Block{
+		{Type: "text", Lines: []Line{textLine(16, "Bold", "Usage")}},
+		bodyBlock("Body text describing usage, long enough to dominate the histogram weighting."),
+	}}
+	return []Page{page1, page2}
+}
+
+func TestClassifyPages_LayoutClassifier(t *testing.T) {
+	blocks := ClassifyPages(synthPages())
+	if len(blocks) == 0 {
+		t.Fatal("expected at least one structured block")
+	}
+	for _, b := range blocks {
+		if !strings.Contains(b.HeaderHierarchy, "Introduction") && !strings.Contains(b.HeaderHierarchy, "Usage") {
+			t.Errorf("unexpected header hierarchy %q", b.HeaderHierarchy)
+		}
+	}
+}
+
+func TestClassifyPages_LegacyClassifier(t *testing.T) {
+	blocks := ClassifyPages(synthPages(), WithLegacyClassifier())
+	if len(blocks) == 0 {
+		t.Fatal("expected at least one structured block under the legacy classifier")
+	}
+}
@@ -0,0 +1,498 @@
+//go:build mupdfcgo
+
+// Package mupdfcgo is a cgo-backed sibling of the mupdf package. It links
+// directly against libmupdf (fitz) instead of shelling out to the mutool
+// CLI, which removes the external-binary dependency, avoids a process fork
+// per call, and lets every operation run against an in-memory buffer so any
+// io.Reader works without first being spooled to a temp file (as
+// processor.Do in the mupdf package does for non-*os.File readers).
+//
+// Building this package requires libmupdf and its headers to be installed
+// and discoverable by cgo (pkg-config or CGO_CFLAGS/CGO_LDFLAGS). It is
+// opt-in via the "mupdfcgo" build tag so the pure-CLI mupdf package keeps
+// working without a C toolchain.
+package mupdfcgo
+
+/*
+#cgo pkg-config: mupdf
+#cgo LDFLAGS: -lmupdf -lmupdfthird -lm
+
+#include <mupdf/fitz.h>
+#include <stdlib.h>
+#include <string.h>
+
+// MuPDF signals errors by longjmp-ing out of the current fz_try block; a
+// fallible call made with no fz_try frame installed longjmps with nowhere
+// to land and aborts the process. Every fitz call below that parses
+// untrusted document bytes (and can therefore throw on a malformed or
+// password-protected PDF) is wrapped here so the Go side gets an error
+// instead of a crash.
+//
+// go_fz_ptr_result carries a pointer-returning call's outcome: err is NULL
+// on success, else a strdup'd copy of fz_caught_message the Go side must
+// free.
+typedef struct {
+	void *ptr;
+	char *err;
+} go_fz_ptr_result;
+
+typedef struct {
+	int value;
+	char *err;
+} go_fz_int_result;
+
+static char *go_fz_dup_error(fz_context *ctx) {
+	const char *msg = fz_caught_message(ctx);
+	if (!msg) {
+		msg = "unknown mupdf error";
+	}
+	return strdup(msg);
+}
+
+static go_fz_ptr_result go_fz_open_document_with_stream(fz_context *ctx, const char *magic, fz_stream *stream) {
+	go_fz_ptr_result res = {0};
+	fz_try(ctx) {
+		res.ptr = fz_open_document_with_stream(ctx, magic, stream);
+	} fz_catch(ctx) {
+		res.ptr = NULL;
+		res.err = go_fz_dup_error(ctx);
+	}
+	return res;
+}
+
+static go_fz_int_result go_fz_count_pages(fz_context *ctx, fz_document *doc) {
+	go_fz_int_result res = {0};
+	fz_try(ctx) {
+		res.value = fz_count_pages(ctx, doc);
+	} fz_catch(ctx) {
+		res.value = 0;
+		res.err = go_fz_dup_error(ctx);
+	}
+	return res;
+}
+
+static go_fz_ptr_result go_fz_load_page(fz_context *ctx, fz_document *doc, int number) {
+	go_fz_ptr_result res = {0};
+	fz_try(ctx) {
+		res.ptr = fz_load_page(ctx, doc, number);
+	} fz_catch(ctx) {
+		res.ptr = NULL;
+		res.err = go_fz_dup_error(ctx);
+	}
+	return res;
+}
+
+static go_fz_ptr_result go_fz_new_stext_page_from_page(fz_context *ctx, fz_page *page, const fz_stext_options *opts) {
+	go_fz_ptr_result res = {0};
+	fz_try(ctx) {
+		res.ptr = fz_new_stext_page_from_page(ctx, page, opts);
+	} fz_catch(ctx) {
+		res.ptr = NULL;
+		res.err = go_fz_dup_error(ctx);
+	}
+	return res;
+}
+
+static go_fz_ptr_result go_fz_new_pixmap_from_page(fz_context *ctx, fz_page *page, fz_matrix ctm, fz_colorspace *cs, int alpha) {
+	go_fz_ptr_result res = {0};
+	fz_try(ctx) {
+		res.ptr = fz_new_pixmap_from_page(ctx, page, ctm, cs, alpha);
+	} fz_catch(ctx) {
+		res.ptr = NULL;
+		res.err = go_fz_dup_error(ctx);
+	}
+	return res;
+}
+*/
+import "C"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"unsafe"
+
+	"github.com/SaiNageswarS/gizmo/core"
+	"github.com/SaiNageswarS/gizmo/mupdf"
+)
+
+// public aliases for registry keys – parallel to mupdf's aliases but
+// suffixed "-native" so both implementations can coexist.
+const (
+	TextProcessor   = "mupdf-text-native"
+	RenderProcessor = "mupdf-render-native"
+)
+
+func init() {
+	core.Register(TextProcessor, NewTextExtractor)
+	core.Register(RenderProcessor, NewRenderer)
+}
+
+// ----------------------------------------------------------------------------
+// fz_context pool
+// ----------------------------------------------------------------------------
+
+// fz_context is not thread-safe, but MuPDF allows cloning a context per
+// goroutine via fz_clone_context. We keep a sync.Pool of cloned contexts
+// seeded from a single base context created lazily on first use.
+
+var (
+	baseCtx  *C.fz_context
+	baseOnce sync.Once
+	baseErr  error
+	ctxPool  sync.Pool
+)
+
+func initBaseContext() {
+	baseOnce.Do(func() {
+		ctx := C.fz_new_context(nil, nil, C.FZ_STORE_DEFAULT)
+		if ctx == nil {
+			baseErr = fmt.Errorf("mupdfcgo: fz_new_context failed")
+			return
+		}
+		C.fz_register_document_handlers(ctx)
+		baseCtx = ctx
+	})
+}
+
+// acquireContext hands back a context cloned from baseCtx, either a fresh
+// clone or one returned to the pool by a previous call.
+func acquireContext() (*C.fz_context, error) {
+	initBaseContext()
+	if baseErr != nil {
+		return nil, baseErr
+	}
+	if v := ctxPool.Get(); v != nil {
+		return v.(*C.fz_context), nil
+	}
+	clone := C.fz_clone_context(baseCtx)
+	if clone == nil {
+		return nil, fmt.Errorf("mupdfcgo: fz_clone_context failed")
+	}
+	return clone, nil
+}
+
+func releaseContext(ctx *C.fz_context) {
+	ctxPool.Put(ctx)
+}
+
+// fzError converts a fz_caught_message copy produced by one of the
+// go_fz_* C helpers into a Go error, freeing the C string. Returns nil if
+// msg is NULL, i.e. the guarded call succeeded.
+func fzError(op string, msg *C.char) error {
+	if msg == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(msg))
+	return fmt.Errorf("mupdfcgo: %s: %s", op, C.GoString(msg))
+}
+
+// ----------------------------------------------------------------------------
+// Processor implementations
+// ----------------------------------------------------------------------------
+
+type processor struct {
+	mode string // "text", "render"
+}
+
+func (p *processor) Do(ctx context.Context, in io.Reader, out io.Writer, opts ...core.Option) error {
+	cfg := core.BuildConfig(opts...)
+
+	buf, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("mupdfcgo: reading input: %w", err)
+	}
+
+	fzCtx, err := acquireContext()
+	if err != nil {
+		return err
+	}
+	defer releaseContext(fzCtx)
+
+	doc, err := openDocument(fzCtx, buf)
+	if err != nil {
+		return err
+	}
+	defer C.fz_drop_document(fzCtx, doc)
+
+	pages := cfg.Pages
+	if len(pages) == 0 {
+		cr := C.go_fz_count_pages(fzCtx, doc)
+		if err := fzError("counting pages", cr.err); err != nil {
+			return err
+		}
+		n := int(cr.value)
+		pages = make([]int, n)
+		for i := range pages {
+			pages[i] = i + 1
+		}
+	}
+
+	switch p.mode {
+	case "text":
+		return renderText(fzCtx, doc, pages, out)
+	case "render":
+		return renderPNG(fzCtx, doc, pages, cfg, out)
+	default:
+		return fmt.Errorf("mupdfcgo: unknown mode %q", p.mode)
+	}
+}
+
+// openDocument copies buf into a MuPDF-owned fz_buffer and opens it as a
+// PDF stream. The copy is required because the document keeps reading from
+// the stream after this function returns, and the Go GC is free to move or
+// collect buf once it does; the stream holds its own reference to the
+// fz_buffer, so the copy lives as long as the document.
+func openDocument(fzCtx *C.fz_context, buf []byte) (*C.fz_document, error) {
+	cbuf := C.CBytes(buf)
+	defer C.free(cbuf)
+
+	fzData := C.fz_new_buffer_from_copied_data(fzCtx, (*C.uchar)(cbuf), C.size_t(len(buf)))
+	if fzData == nil {
+		return nil, fmt.Errorf("mupdfcgo: fz_new_buffer_from_copied_data failed")
+	}
+	defer C.fz_drop_buffer(fzCtx, fzData)
+
+	stream := C.fz_open_buffer(fzCtx, fzData)
+	if stream == nil {
+		return nil, fmt.Errorf("mupdfcgo: fz_open_buffer failed")
+	}
+	defer C.fz_drop_stream(fzCtx, stream)
+
+	magic := C.CString("application/pdf")
+	defer C.free(unsafe.Pointer(magic))
+
+	res := C.go_fz_open_document_with_stream(fzCtx, magic, stream)
+	if err := fzError("opening document", res.err); err != nil {
+		return nil, err
+	}
+	if res.ptr == nil {
+		return nil, fmt.Errorf("mupdfcgo: fz_open_document_with_stream failed")
+	}
+	return (*C.fz_document)(res.ptr), nil
+}
+
+// renderText extracts plain text for the given 1-based pages using MuPDF's
+// structured-text extractor and its built-in plain-text writer, avoiding a
+// manual block/line/char walk.
+func renderText(fzCtx *C.fz_context, doc *C.fz_document, pages []int, out io.Writer) error {
+	fzBuf := C.fz_new_buffer(fzCtx, 0)
+	defer C.fz_drop_buffer(fzCtx, fzBuf)
+
+	output := C.fz_new_output_with_buffer(fzCtx, fzBuf)
+	defer C.fz_drop_output(fzCtx, output)
+
+	opts := C.fz_stext_options{flags: 0}
+	for _, p := range pages {
+		pageRes := C.go_fz_load_page(fzCtx, doc, C.int(p-1))
+		if err := fzError(fmt.Sprintf("loading page %d", p), pageRes.err); err != nil {
+			return err
+		}
+		if pageRes.ptr == nil {
+			return fmt.Errorf("mupdfcgo: fz_load_page(%d) failed", p)
+		}
+		page := (*C.fz_page)(pageRes.ptr)
+
+		textRes := C.go_fz_new_stext_page_from_page(fzCtx, page, &opts)
+		if err := fzError(fmt.Sprintf("extracting structured text for page %d", p), textRes.err); err != nil {
+			C.fz_drop_page(fzCtx, page)
+			return err
+		}
+		if textRes.ptr == nil {
+			C.fz_drop_page(fzCtx, page)
+			return fmt.Errorf("mupdfcgo: fz_new_stext_page_from_page(%d) failed", p)
+		}
+		text := (*C.fz_stext_page)(textRes.ptr)
+
+		C.fz_print_stext_page_as_text(fzCtx, output, text)
+		C.fz_drop_stext_page(fzCtx, text)
+		C.fz_drop_page(fzCtx, page)
+	}
+	C.fz_close_output(fzCtx, output)
+
+	_, err := out.Write(C.GoBytes(unsafe.Pointer(fzBuf.data), C.int(fzBuf.len)))
+	return err
+}
+
+// renderPNG rasterizes the given pages at cfg.Extra["dpi"] (72 by default)
+// and writes each page's PNG bytes back-to-back to out.
+func renderPNG(fzCtx *C.fz_context, doc *C.fz_document, pages []int, cfg *core.Config, out io.Writer) error {
+	dpi := 72
+	if v, ok := cfg.Extra["dpi"].(int); ok && v > 0 {
+		dpi = v
+	}
+	scale := C.float(dpi) / 72.0
+	ctm := C.fz_scale(scale, scale)
+
+	for _, p := range pages {
+		pageRes := C.go_fz_load_page(fzCtx, doc, C.int(p-1))
+		if err := fzError(fmt.Sprintf("loading page %d", p), pageRes.err); err != nil {
+			return err
+		}
+		if pageRes.ptr == nil {
+			return fmt.Errorf("mupdfcgo: fz_load_page(%d) failed", p)
+		}
+		page := (*C.fz_page)(pageRes.ptr)
+
+		pixRes := C.go_fz_new_pixmap_from_page(fzCtx, page, ctm, C.fz_device_rgb(fzCtx), 0)
+		if err := fzError(fmt.Sprintf("rendering page %d", p), pixRes.err); err != nil {
+			C.fz_drop_page(fzCtx, page)
+			return err
+		}
+		if pixRes.ptr == nil {
+			C.fz_drop_page(fzCtx, page)
+			return fmt.Errorf("mupdfcgo: fz_new_pixmap_from_page(%d) failed", p)
+		}
+		pix := (*C.fz_pixmap)(pixRes.ptr)
+		fzBuf := C.fz_new_buffer(fzCtx, 0)
+		output := C.fz_new_output_with_buffer(fzCtx, fzBuf)
+		C.fz_write_pixmap_as_png(fzCtx, output, pix)
+		C.fz_close_output(fzCtx, output)
+
+		_, err := out.Write(C.GoBytes(unsafe.Pointer(fzBuf.data), C.int(fzBuf.len)))
+
+		C.fz_drop_output(fzCtx, output)
+		C.fz_drop_buffer(fzCtx, fzBuf)
+		C.fz_drop_pixmap(fzCtx, pix)
+		C.fz_drop_page(fzCtx, page)
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewTextExtractor() core.Processor {
+	return &processor{mode: "text"}
+}
+
+func NewRenderer() core.Processor {
+	return &processor{mode: "render"}
+}
+
+// ----------------------------------------------------------------------------
+// Convenience wrappers – parallel to the mupdf package's exported helpers.
+// ----------------------------------------------------------------------------
+
+// ExtractText extracts text for the given 1-based pages (or all pages when
+// none given) directly from an in-memory reader, no temp file required.
+func ExtractText(ctx context.Context, r io.Reader, pages ...int) (string, error) {
+	var buf bytes.Buffer
+	proc := NewTextExtractor()
+	err := proc.Do(ctx, r, &buf, core.WithPages(pages...))
+	return buf.String(), err
+}
+
+// GetPageCount returns the total number of pages in the PDF read from r.
+func GetPageCount(ctx context.Context, r io.Reader) (int, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	fzCtx, err := acquireContext()
+	if err != nil {
+		return 0, err
+	}
+	defer releaseContext(fzCtx)
+
+	doc, err := openDocument(fzCtx, raw)
+	if err != nil {
+		return 0, err
+	}
+	defer C.fz_drop_document(fzCtx, doc)
+
+	cr := C.go_fz_count_pages(fzCtx, doc)
+	if err := fzError("counting pages", cr.err); err != nil {
+		return 0, err
+	}
+	return int(cr.value), nil
+}
+
+// ExtractStructuredText mirrors mupdf.ExtractStructuredText's two-pass
+// header/body classification, but walks the in-process fz_stext_page tree
+// for every page instead of shelling out to `mutool draw -F stext.json`
+// once per batch.
+func ExtractStructuredText(ctx context.Context, r io.Reader) ([]mupdf.StructuredBlock, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fzCtx, err := acquireContext()
+	if err != nil {
+		return nil, err
+	}
+	defer releaseContext(fzCtx)
+
+	doc, err := openDocument(fzCtx, raw)
+	if err != nil {
+		return nil, err
+	}
+	defer C.fz_drop_document(fzCtx, doc)
+
+	cr := C.go_fz_count_pages(fzCtx, doc)
+	if err := fzError("counting pages", cr.err); err != nil {
+		return nil, err
+	}
+	n := int(cr.value)
+	if n == 0 {
+		return nil, fmt.Errorf("mupdfcgo: empty PDF or page count undetected")
+	}
+
+	pages := make([]mupdf.Page, n)
+	for i := 0; i < n; i++ {
+		page, err := stextPage(fzCtx, doc, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("mupdfcgo: page %d: %w", i+1, err)
+		}
+		pages[i] = page
+	}
+
+	return mupdf.ClassifyPages(pages), nil
+}
+
+// stextPage renders page p (1-based) to MuPDF's stext.json representation
+// and decodes it into the mupdf package's Page/Block/Line types, so the
+// classification logic in mupdf.ClassifyPages can be shared verbatim.
+func stextPage(fzCtx *C.fz_context, doc *C.fz_document, p int) (mupdf.Page, error) {
+	pageRes := C.go_fz_load_page(fzCtx, doc, C.int(p-1))
+	if err := fzError(fmt.Sprintf("loading page %d", p), pageRes.err); err != nil {
+		return mupdf.Page{}, err
+	}
+	if pageRes.ptr == nil {
+		return mupdf.Page{}, fmt.Errorf("fz_load_page(%d) failed", p)
+	}
+	page := (*C.fz_page)(pageRes.ptr)
+	defer C.fz_drop_page(fzCtx, page)
+
+	textRes := C.go_fz_new_stext_page_from_page(fzCtx, page, nil)
+	if err := fzError(fmt.Sprintf("extracting structured text for page %d", p), textRes.err); err != nil {
+		return mupdf.Page{}, err
+	}
+	if textRes.ptr == nil {
+		return mupdf.Page{}, fmt.Errorf("fz_new_stext_page_from_page(%d) failed", p)
+	}
+	text := (*C.fz_stext_page)(textRes.ptr)
+	defer C.fz_drop_stext_page(fzCtx, text)
+
+	fzBuf := C.fz_new_buffer(fzCtx, 0)
+	defer C.fz_drop_buffer(fzCtx, fzBuf)
+	output := C.fz_new_output_with_buffer(fzCtx, fzBuf)
+	C.fz_print_stext_page_as_json(fzCtx, output, text, 1)
+	C.fz_close_output(fzCtx, output)
+	defer C.fz_drop_output(fzCtx, output)
+
+	raw := C.GoBytes(unsafe.Pointer(fzBuf.data), C.int(fzBuf.len))
+	var doc2 struct {
+		Blocks []mupdf.Block `json:"blocks"`
+	}
+	if err := json.Unmarshal(raw, &doc2); err != nil {
+		return mupdf.Page{}, fmt.Errorf("unmarshal stext json: %w", err)
+	}
+	return mupdf.Page{Blocks: doc2.Blocks}, nil
+}
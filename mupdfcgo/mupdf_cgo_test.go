@@ -0,0 +1,17 @@
+//go:build mupdfcgo
+
+package mupdfcgo
+
+import (
+	"testing"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+func TestRegistration(t *testing.T) {
+	for _, alias := range []string{TextProcessor, RenderProcessor} {
+		if _, err := core.Get(alias); err != nil {
+			t.Errorf("expected alias %q to be registered: %v", alias, err)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Package pdfcpu wraps github.com/pdfcpu/pdfcpu to give gizmo a PDF
+// *manipulation* story (merge, split, forms, watermarking, encryption,
+// attachments) alongside the mupdf package's text/render extraction.
+//
+// It follows the same pattern as the mupdf adapter: each operation is a
+// distinct core.Processor registered under its own alias from an init()
+// function, and operation-specific parameters travel through
+// core.WithExtra rather than growing the Processor interface.
+package pdfcpu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	pdfcpuapi "github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+// public aliases for registry keys
+const (
+	MergeProcessor       = "pdfcpu-merge"
+	SplitProcessor       = "pdfcpu-split"
+	FormExtractProcessor = "pdfcpu-form-extract"
+	FormFillProcessor    = "pdfcpu-form-fill"
+	StampProcessor       = "pdfcpu-stamp"
+	EncryptProcessor     = "pdfcpu-encrypt"
+	DecryptProcessor     = "pdfcpu-decrypt"
+	AttachmentsProcessor = "pdfcpu-attachments"
+)
+
+func init() {
+	core.Register(MergeProcessor, NewMerger)
+	core.Register(SplitProcessor, NewSplitter)
+	core.Register(FormExtractProcessor, NewFormExtractor)
+	core.Register(FormFillProcessor, NewFormFiller)
+	core.Register(StampProcessor, NewStamper)
+	core.Register(EncryptProcessor, NewEncryptor)
+	core.Register(DecryptProcessor, NewDecryptor)
+	core.Register(AttachmentsProcessor, NewAttachmentLister)
+}
+
+// Processor implementation ---------------------------------------------------
+
+type processor struct {
+	op string
+}
+
+func (p *processor) Do(ctx context.Context, in io.Reader, out io.Writer, opts ...core.Option) error {
+	cfg := core.BuildConfig(opts...)
+
+	inFile, cleanup, err := spoolToTemp(cfg.WorkDir, in)
+	if err != nil {
+		return fmt.Errorf("pdfcpu: spooling input: %w", err)
+	}
+	defer cleanup()
+
+	switch p.op {
+	case "merge":
+		return doMerge(cfg, inFile, out)
+	case "split":
+		return doSplit(cfg, inFile, out)
+	case "form-extract":
+		return doFormExtract(inFile, out)
+	case "form-fill":
+		return doFormFill(cfg, inFile, out)
+	case "stamp":
+		return doStamp(cfg, inFile, out)
+	case "encrypt":
+		return doEncrypt(cfg, inFile, out)
+	case "decrypt":
+		return doDecrypt(cfg, inFile, out)
+	case "attachments":
+		return doAttachments(inFile, out)
+	default:
+		return fmt.Errorf("pdfcpu: unknown op %q", p.op)
+	}
+}
+
+// spoolToTemp copies in to a temp *.pdf file under dir (WorkDir, or the OS
+// default when empty) since the pdfcpu API works against file paths rather
+// than io.Reader/io.Writer. The returned cleanup removes the temp file.
+func spoolToTemp(dir string, in io.Reader) (path string, cleanup func(), err error) {
+	f, err := os.CreateTemp(dir, "gizmo-pdfcpu-*.pdf")
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, in); err != nil {
+		os.Remove(f.Name())
+		return "", nil, err
+	}
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
+
+// withOutTemp runs fn against a fresh temp output path, then streams the
+// resulting file to out and removes the temp file.
+func withOutTemp(dir string, out io.Writer, fn func(outFile string) error) error {
+	f, err := os.CreateTemp(dir, "gizmo-pdfcpu-out-*.pdf")
+	if err != nil {
+		return err
+	}
+	outFile := f.Name()
+	f.Close()
+	os.Remove(outFile) // pdfcpu wants to create the file itself
+	defer os.Remove(outFile)
+
+	if err := fn(outFile); err != nil {
+		return err
+	}
+
+	r, err := os.Open(outFile)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, err = io.Copy(out, r)
+	return err
+}
+
+// Operations ------------------------------------------------------------------
+
+// doMerge merges inFile with any additional files supplied via
+// core.WithExtra("files", []string{...}) and streams the result to out.
+func doMerge(cfg *core.Config, inFile string, out io.Writer) error {
+	extra, _ := cfg.Extra["files"].([]string)
+	inFiles := append([]string{inFile}, extra...)
+
+	return withOutTemp(cfg.WorkDir, out, func(outFile string) error {
+		return pdfcpuapi.MergeCreateFile(inFiles, outFile, false, model.NewDefaultConfiguration())
+	})
+}
+
+// doSplit splits inFile into page spans of core.WithExtra("span", n) pages
+// (default 1 ⇒ one file per page), writing the resulting file paths as a
+// JSON array to out.
+//
+// Unlike the other operations, the split output files are the result, not
+// scratch space, so they are never removed here – the caller owns
+// everything doSplit writes to dir (and, when cfg.WorkDir is empty, owns
+// the directory created for it too) and is responsible for cleaning it up
+// once it's done reading the listed files.
+func doSplit(cfg *core.Config, inFile string, out io.Writer) error {
+	span, _ := cfg.Extra["span"].(int)
+	if span <= 0 {
+		span = 1
+	}
+
+	dir := cfg.WorkDir
+	if dir == "" {
+		var err error
+		dir, err = os.MkdirTemp("", "gizmo-pdfcpu-split-*")
+		if err != nil {
+			return err
+		}
+	}
+
+	// dir may already hold other files (with cfg.WorkDir set, the spooled
+	// input itself), so only the entries SplitFile adds are reported.
+	existing := map[string]bool{}
+	if before, err := os.ReadDir(dir); err == nil {
+		for _, e := range before {
+			existing[e.Name()] = true
+		}
+	}
+
+	if err := pdfcpuapi.SplitFile(inFile, dir, span, model.NewDefaultConfiguration()); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && !existing[e.Name()] {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	return json.NewEncoder(out).Encode(files)
+}
+
+// doFormExtract writes inFile's form field data as JSON to out.
+func doFormExtract(inFile string, out io.Writer) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	form, err := pdfcpuapi.ExportForm(f, inFile, model.NewDefaultConfiguration())
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(out).Encode(form)
+}
+
+// doFormFill fills inFile's form fields from the JSON data referenced by
+// core.WithExtra("formDataFile", path) and streams the filled PDF to out.
+func doFormFill(cfg *core.Config, inFile string, out io.Writer) error {
+	formFile, _ := cfg.Extra["formDataFile"].(string)
+	if formFile == "" {
+		return fmt.Errorf("pdfcpu: form-fill requires core.WithExtra(\"formDataFile\", path)")
+	}
+
+	return withOutTemp(cfg.WorkDir, out, func(outFile string) error {
+		return pdfcpuapi.FillFormFile(inFile, formFile, outFile, model.NewDefaultConfiguration())
+	})
+}
+
+// doStamp applies the watermark/stamp text from
+// core.WithExtra("stampText", "...") to inFile and streams the result.
+func doStamp(cfg *core.Config, inFile string, out io.Writer) error {
+	text, _ := cfg.Extra["stampText"].(string)
+	if text == "" {
+		return fmt.Errorf("pdfcpu: stamp requires core.WithExtra(\"stampText\", string)")
+	}
+	onTop, _ := cfg.Extra["onTop"].(bool)
+
+	return withOutTemp(cfg.WorkDir, out, func(outFile string) error {
+		wm, err := pdfcpuapi.TextWatermark(text, "", onTop, false, types.POINTS)
+		if err != nil {
+			return err
+		}
+		return pdfcpuapi.AddWatermarksFile(inFile, outFile, nil, wm, model.NewDefaultConfiguration())
+	})
+}
+
+// doEncrypt encrypts inFile using core.WithExtra("password", "...").
+func doEncrypt(cfg *core.Config, inFile string, out io.Writer) error {
+	pw, _ := cfg.Extra["password"].(string)
+	if pw == "" {
+		return fmt.Errorf("pdfcpu: encrypt requires core.WithExtra(\"password\", string)")
+	}
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = pw
+	conf.OwnerPW = pw
+
+	return withOutTemp(cfg.WorkDir, out, func(outFile string) error {
+		return pdfcpuapi.EncryptFile(inFile, outFile, conf)
+	})
+}
+
+// doDecrypt decrypts inFile using core.WithExtra("password", "...").
+func doDecrypt(cfg *core.Config, inFile string, out io.Writer) error {
+	pw, _ := cfg.Extra["password"].(string)
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = pw
+
+	return withOutTemp(cfg.WorkDir, out, func(outFile string) error {
+		return pdfcpuapi.DecryptFile(inFile, outFile, conf)
+	})
+}
+
+// doAttachments writes the attachments embedded in inFile as a JSON array
+// to out.
+func doAttachments(inFile string, out io.Writer) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	attachments, err := pdfcpuapi.Attachments(f, model.NewDefaultConfiguration())
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(out).Encode(attachments)
+}
+
+// Constructors ------------------------------------------------------------------
+
+func NewMerger() core.Processor           { return &processor{op: "merge"} }
+func NewSplitter() core.Processor         { return &processor{op: "split"} }
+func NewFormExtractor() core.Processor    { return &processor{op: "form-extract"} }
+func NewFormFiller() core.Processor       { return &processor{op: "form-fill"} }
+func NewStamper() core.Processor          { return &processor{op: "stamp"} }
+func NewEncryptor() core.Processor        { return &processor{op: "encrypt"} }
+func NewDecryptor() core.Processor        { return &processor{op: "decrypt"} }
+func NewAttachmentLister() core.Processor { return &processor{op: "attachments"} }
@@ -0,0 +1,111 @@
+package pdfcpu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SaiNageswarS/gizmo/core"
+)
+
+const fixturePDF = "../testdata/SaiNageswarS_Resume.pdf"
+
+func openFixture(t *testing.T) *os.File {
+	t.Helper()
+	f, err := os.Open(fixturePDF)
+	if err != nil {
+		t.Skipf("fixture PDF unavailable: %v", err)
+	}
+	return f
+}
+
+func TestMerger_Do(t *testing.T) {
+	f := openFixture(t)
+	defer f.Close()
+
+	var out bytes.Buffer
+	merger := NewMerger()
+	err := merger.Do(context.Background(), f, &out, core.WithExtra("files", []string{fixturePDF}))
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF")) {
+		t.Errorf("expected merged output to start with the PDF magic, got %q", out.Bytes()[:minInt(8, out.Len())])
+	}
+}
+
+func TestSplitter_Do(t *testing.T) {
+	f := openFixture(t)
+	defer f.Close()
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	splitter := NewSplitter()
+	cfg := []core.Option{core.WithWorkDir(dir)}
+	if err := splitter.Do(context.Background(), f, &out, cfg...); err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+
+	var files []string
+	if err := json.Unmarshal(out.Bytes(), &files); err != nil {
+		t.Fatalf("decoding split output: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("expected at least one split output file")
+	}
+	for _, path := range files {
+		if filepath.Dir(path) != dir {
+			t.Errorf("expected split file %q to live under WorkDir %q", path, dir)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("split output file missing: %v", err)
+		}
+	}
+}
+
+func TestStamper_Do(t *testing.T) {
+	f := openFixture(t)
+	defer f.Close()
+
+	var out bytes.Buffer
+	stamper := NewStamper()
+	err := stamper.Do(context.Background(), f, &out, core.WithExtra("stampText", "CONFIDENTIAL"))
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF")) {
+		t.Errorf("expected stamped output to start with the PDF magic, got %q", out.Bytes()[:minInt(8, out.Len())])
+	}
+}
+
+func TestSpoolToTemp(t *testing.T) {
+	r := bytes.NewReader([]byte("%PDF-fake"))
+	path, cleanup, err := spoolToTemp(t.TempDir(), r)
+	if err != nil {
+		t.Fatalf("spoolToTemp failed: %v", err)
+	}
+	defer cleanup()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read spooled file: %v", err)
+	}
+	if string(b) != "%PDF-fake" {
+		t.Errorf("spooled file contents = %q, want %q", b, "%PDF-fake")
+	}
+
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected cleanup to remove the spooled temp file, stat err = %v", err)
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}